@@ -0,0 +1,94 @@
+// Command beatoz-abigen generates type-safe Go bindings of EVM contracts for the beatoz-sdk-go.
+//
+// Usage:
+//
+//	beatoz-abigen -artifact MyToken.json -pkg token -out token.go
+//	beatoz-abigen -combined-json combined.json -contract MyToken -pkg token
+//	beatoz-abigen -abi MyToken.abi -bin MyToken.bin -pkg token -type MyToken
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/beatoz/beatoz-sdk-go/vm"
+	"github.com/beatoz/beatoz-sdk-go/vm/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func main() {
+	artifact := flag.String("artifact", "", "truffle, hardhat or foundry artifact file")
+	combined := flag.String("combined-json", "", "solc --combined-json output file")
+	contract := flag.String("contract", "", "contract name in the -combined-json file")
+	abiPath := flag.String("abi", "", "abi file (with -bin)")
+	binPath := flag.String("bin", "", "bytecode file (with -abi)")
+	pkg := flag.String("pkg", "", "package name of the generated code")
+	typeName := flag.String("type", "", "struct name of the binding (default: the contract or file name)")
+	out := flag.String("out", "", "output file (default: stdout)")
+	flag.Parse()
+
+	if err := run(*artifact, *combined, *contract, *abiPath, *binPath, *pkg, *typeName, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "beatoz-abigen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(artifact, combined, contract, abiPath, binPath, pkg, typeName, out string) error {
+	if pkg == "" {
+		return fmt.Errorf("-pkg is required")
+	}
+
+	var (
+		ec   *vm.EVMContract
+		name string
+		err  error
+	)
+	switch {
+	case artifact != "":
+		ec, err = vm.NewEVMContract(artifact)
+		name = fileName(artifact)
+	case combined != "":
+		if contract == "" {
+			return fmt.Errorf("-contract is required with -combined-json")
+		}
+		ec, err = vm.NewEVMContractFromCombinedJSON(combined, contract)
+		name = contract
+		if i := strings.LastIndex(name, ":"); i >= 0 {
+			name = name[i+1:]
+		}
+	case abiPath != "" && binPath != "":
+		ec, err = vm.NewEVMContractFromABIBin(abiPath, binPath)
+		name = fileName(abiPath)
+	default:
+		return fmt.Errorf("one of -artifact, -combined-json or -abi with -bin is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	if typeName == "" {
+		typeName = abi.ToCamelCase(name)
+	}
+	src, err := bind.Generate(ec, pkg, typeName)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0644)
+}
+
+// fileName returns the base name of path without its extensions, e.g. "MyToken" of "out/MyToken.sol/MyToken.json".
+func fileName(path string) string {
+	name := filepath.Base(path)
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
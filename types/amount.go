@@ -0,0 +1,69 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/holiman/uint256"
+)
+
+// Amount is an amount of the smallest unit decoded from a JSON string in hex (0x-prefixed) or decimal.
+// An empty string or null is decoded as 0. It is encoded as a decimal string.
+type Amount uint256.Int
+
+// ParseAmount parses s in hex (0x-prefixed) or decimal. An empty s is parsed as 0.
+func ParseAmount(s string) (*uint256.Int, error) {
+	switch {
+	case s == "":
+		return uint256.NewInt(0), nil
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		// uint256.FromHex rejects leading zeros, which some encoders emit.
+		digits := strings.TrimLeft(s[2:], "0")
+		if digits == "" && len(s) > 2 {
+			digits = "0"
+		}
+		return uint256.FromHex("0x" + digits)
+	default:
+		return uint256.FromDecimal(s)
+	}
+}
+
+func NewAmount(v *uint256.Int) Amount {
+	if v == nil {
+		return Amount{}
+	}
+	return Amount(*v)
+}
+
+// Int returns a copy of the amount as *uint256.Int.
+func (a *Amount) Int() *uint256.Int {
+	return new(uint256.Int).Set((*uint256.Int)(a))
+}
+
+func (a Amount) String() string {
+	return (*uint256.Int)(&a).Dec()
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + a.String() + `"`), nil
+}
+
+func (a *Amount) UnmarshalJSON(bz []byte) error {
+	if bytes.Equal(bz, []byte("null")) {
+		*a = Amount{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(bz, &s); err != nil {
+		return fmt.Errorf("amount must be a string: %w", err)
+	}
+	v, err := ParseAmount(s)
+	if err != nil {
+		return fmt.Errorf("wrong amount(%q): %w", s, err)
+	}
+	*a = Amount(*v)
+	return nil
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	ctrlertypes "github.com/beatoz/beatoz-go/ctrlers/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+type BlockResult struct {
+	*coretypes.ResultBlock
+	// TrxObjs has the decoded transactions of the block in the same order as Block.Data.Txs.
+	TrxObjs []*ctrlertypes.Trx `json:"trx_objs"`
+}
+
+func NewBlockResult(resultBlock *coretypes.ResultBlock) (*BlockResult, error) {
+	ret := &BlockResult{
+		ResultBlock: resultBlock,
+	}
+	if resultBlock.Block == nil {
+		return ret, nil
+	}
+
+	for _, tx := range resultBlock.Block.Data.Txs {
+		trx := &ctrlertypes.Trx{}
+		if err := trx.Decode(tx); err != nil {
+			return nil, err
+		}
+		ret.TrxObjs = append(ret.TrxObjs, trx)
+	}
+	return ret, nil
+}
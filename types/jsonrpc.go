@@ -30,3 +30,22 @@ func NewRequest(id int64, method string, args ...interface{}) (*JSONRpcReq, erro
 	}
 	return ret, nil
 }
+
+type JSONRpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// RpcError decodes resp.Error. It returns nil if resp has no error.
+// If the error can not be decoded, its raw text is set as Message.
+func (resp *JSONRpcResp) RpcError() *JSONRpcError {
+	if len(resp.Error) == 0 || string(resp.Error) == "null" {
+		return nil
+	}
+	ret := &JSONRpcError{}
+	if err := json.Unmarshal(resp.Error, ret); err != nil {
+		ret.Message = string(resp.Error)
+	}
+	return ret
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"bytes"
+
+	ctrlertypes "github.com/beatoz/beatoz-go/ctrlers/types"
+	btztypes "github.com/beatoz/beatoz-go/types"
+	btzbytes "github.com/beatoz/beatoz-go/types/bytes"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+type PendingTrx struct {
+	Hash   btzbytes.HexBytes `json:"hash"`
+	TrxObj *ctrlertypes.Trx  `json:"trx_obj"`
+}
+
+type UnconfirmedTrxsResult struct {
+	// Count is the number of txs in Txs.
+	Count int `json:"n_txs"`
+	// Total is the number of txs in the mempool.
+	Total      int           `json:"total"`
+	TotalBytes int64         `json:"total_bytes"`
+	Txs        []*PendingTrx `json:"txs"`
+}
+
+func NewPendingTrxs(txs []tmtypes.Tx) ([]*PendingTrx, error) {
+	var ret []*PendingTrx
+	for _, tx := range txs {
+		trx := &ctrlertypes.Trx{}
+		if err := trx.Decode(tx); err != nil {
+			return nil, err
+		}
+		ret = append(ret, &PendingTrx{
+			Hash:   tx.Hash(),
+			TrxObj: trx,
+		})
+	}
+	return ret, nil
+}
+
+// FilterBySender returns the pending txs sent from addr ordered as in the mempool.
+func (r *UnconfirmedTrxsResult) FilterBySender(addr btztypes.Address) []*PendingTrx {
+	var ret []*PendingTrx
+	for _, ptx := range r.Txs {
+		if bytes.Equal(ptx.TrxObj.From, addr) {
+			ret = append(ret, ptx)
+		}
+	}
+	return ret
+}
+
+// Find returns the pending tx with txhash or nil if there is no such tx.
+func (r *UnconfirmedTrxsResult) Find(txhash []byte) *PendingTrx {
+	for _, ptx := range r.Txs {
+		if bytes.Equal(ptx.Hash, txhash) {
+			return ptx
+		}
+	}
+	return nil
+}
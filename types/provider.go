@@ -3,3 +3,22 @@ package types
 type Provider interface {
 	Call(req *JSONRpcReq) (*JSONRpcResp, error)
 }
+
+// ProviderFunc adapts an ordinary function to the Provider interface.
+type ProviderFunc func(req *JSONRpcReq) (*JSONRpcResp, error)
+
+func (f ProviderFunc) Call(req *JSONRpcReq) (*JSONRpcResp, error) {
+	return f(req)
+}
+
+// Middleware wraps a Provider to add behavior around Call.
+type Middleware func(next Provider) Provider
+
+// Chain wraps provider with the given middlewares.
+// The first middleware is the outermost one, so it sees each call first.
+func Chain(provider Provider, mws ...Middleware) Provider {
+	for i := len(mws) - 1; i >= 0; i-- {
+		provider = mws[i](provider)
+	}
+	return provider
+}
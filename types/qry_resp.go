@@ -31,9 +31,9 @@ type RespQueryDelegatee struct {
 
 type RespQueryReward struct {
 	Address   types.Address `json:"address,omitempty"`
-	Issued    string        `json:"issued,omitempty"`
-	Withdrawn string        `json:"withdrawn,omitempty"`
-	Slashed   string        `json:"slashed,omitempty"`
-	Cumulated string        `json:"cumulated,omitempty"`
+	Issued    Amount        `json:"issued,omitempty"`
+	Withdrawn Amount        `json:"withdrawn,omitempty"`
+	Slashed   Amount        `json:"slashed,omitempty"`
+	Cumulated Amount        `json:"cumulated,omitempty"`
 	Height    int64         `json:"height,omitempty"`
 }
@@ -9,3 +9,8 @@ type TrxResult struct {
 	*coretypes.ResultTx
 	TrxObj *ctrlertypes.Trx `json:"trx_obj"`
 }
+
+type TrxSearchResult struct {
+	Txs        []*TrxResult `json:"txs"`
+	TotalCount int          `json:"total_count"`
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	ctrlertypes "github.com/beatoz/beatoz-go/ctrlers/types"
+	btztypes "github.com/beatoz/beatoz-go/types"
+)
+
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// TxQuery builds a tendermint event query for tx_search.
+// All conditions are combined with AND.
+// A condition that can't be expressed in the query makes the query invalid, which Err reports.
+type TxQuery struct {
+	conds []string
+	err   error
+}
+
+func NewTxQuery() *TxQuery {
+	return &TxQuery{}
+}
+
+func (q *TxQuery) Sender(addr btztypes.Address) *TxQuery {
+	return q.Attr("tx", ctrlertypes.EVENT_ATTR_TXSENDER, addr.String())
+}
+
+func (q *TxQuery) Receiver(addr btztypes.Address) *TxQuery {
+	return q.Attr("tx", ctrlertypes.EVENT_ATTR_TXRECVER, addr.String())
+}
+
+// AddrPair matches the successful txs sent from 'from' to 'to'.
+func (q *TxQuery) AddrPair(from, to btztypes.Address) *TxQuery {
+	return q.Attr("tx", ctrlertypes.EVENT_ATTR_ADDRPAIR, from.String()+to.String())
+}
+
+// TrxType matches the txs of the payload type (e.g. ctrlertypes.TRX_TRANSFER).
+func (q *TxQuery) TrxType(trxType int32) *TxQuery {
+	return q.Attr("tx", ctrlertypes.EVENT_ATTR_TXTYPE, ctrlertypes.TrxTypeString(trxType))
+}
+
+func (q *TxQuery) Hash(txhash []byte) *TxQuery {
+	return q.Where("tx.hash", "=", fmt.Sprintf("%X", txhash))
+}
+
+func (q *TxQuery) Height(height int64) *TxQuery {
+	return q.Where("tx.height", "=", height)
+}
+
+// HeightRange matches the txs in [from, to]. A bound of 0 or less is ignored.
+func (q *TxQuery) HeightRange(from, to int64) *TxQuery {
+	if from > 0 {
+		q.Where("tx.height", ">=", from)
+	}
+	if to > 0 {
+		q.Where("tx.height", "<=", to)
+	}
+	return q
+}
+
+// Attr matches the event attribute '<eventType>.<key> = value'.
+func (q *TxQuery) Attr(eventType, key, value string) *TxQuery {
+	return q.Where(eventType+"."+key, "=", value)
+}
+
+// Where adds the condition '<key> <op> <value>'.
+// op is one of "=", "<", "<=", ">", ">=", "CONTAINS" and "EXISTS".
+// A string value is quoted and a numeric value is not.
+// The query syntax has no escape for a quote, so a string value with "'" makes the query invalid.
+func (q *TxQuery) Where(key, op string, value interface{}) *TxQuery {
+	if op == "EXISTS" {
+		q.conds = append(q.conds, key+" EXISTS")
+		return q
+	}
+
+	var v string
+	switch val := value.(type) {
+	case string:
+		if strings.Contains(val, "'") && q.err == nil {
+			q.err = fmt.Errorf("value of %v can't contain a single quote: %q", key, val)
+		}
+		v = "'" + val + "'"
+	default:
+		v = fmt.Sprintf("%v", val)
+	}
+	q.conds = append(q.conds, fmt.Sprintf("%s %s %s", key, op, v))
+	return q
+}
+
+// Err returns the error of the first invalid condition, or nil if the query is valid.
+func (q *TxQuery) Err() error {
+	return q.err
+}
+
+func (q *TxQuery) String() string {
+	return strings.Join(q.conds, " AND ")
+}
@@ -0,0 +1,192 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/holiman/uint256"
+)
+
+// Unit is a denomination of BTOZ. Decimals is the exponent of 10 to convert it to grans, the smallest unit.
+type Unit struct {
+	Name     string
+	Decimals int
+}
+
+var (
+	Grans  = Unit{Name: "grans", Decimals: 0}
+	Kgrans = Unit{Name: "Kgrans", Decimals: 3}
+	Mgrans = Unit{Name: "Mgrans", Decimals: 6}
+	// Ggrans is the unit gas prices are usually written in. (e.g. 250 Ggrans)
+	Ggrans = Unit{Name: "Ggrans", Decimals: 9}
+	BTOZ   = Unit{Name: "BTOZ", Decimals: 18}
+)
+
+var units = []Unit{Grans, Kgrans, Mgrans, Ggrans, BTOZ}
+
+// ParseUnit returns the unit named name, ignoring the case. "gran" and "BEATOZ" are also accepted.
+func ParseUnit(name string) (Unit, error) {
+	switch strings.ToLower(name) {
+	case "gran":
+		return Grans, nil
+	case "beatoz":
+		return BTOZ, nil
+	}
+	for _, u := range units {
+		if strings.EqualFold(u.Name, name) {
+			return u, nil
+		}
+	}
+	return Unit{}, fmt.Errorf("unknown unit: %q", name)
+}
+
+type RoundingMode int
+
+const (
+	// RoundExact fails if the value can't be represented without rounding.
+	RoundExact RoundingMode = iota
+	// RoundDown rounds toward zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundHalfUp rounds to the nearest, and away from zero on a tie.
+	RoundHalfUp
+	// RoundHalfEven rounds to the nearest, and to the even one on a tie.
+	RoundHalfEven
+)
+
+var ErrInexact = errors.New("value can not be represented exactly")
+
+// ParseUnits parses a string such as "1.5 BTOZ" or "250Ggrans" into grans.
+// If s has no unit, defUnit is used.
+// Digits below a gran are rounded by mode.
+func ParseUnits(s string, defUnit Unit, mode RoundingMode) (*uint256.Int, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.')
+	})
+	if i < 0 {
+		return ParseUnitAmount(s, defUnit, mode)
+	} else if i == 0 {
+		return nil, fmt.Errorf("wrong number: %q", s)
+	}
+	unit, err := ParseUnit(strings.TrimSpace(s[i:]))
+	if err != nil {
+		return nil, err
+	}
+	return ParseUnitAmount(strings.TrimSpace(s[:i]), unit, mode)
+}
+
+// ParseBTOZ parses s into grans. If s has no unit, it is in BTOZ.
+// It fails if s has digits below a gran.
+func ParseBTOZ(s string) (*uint256.Int, error) {
+	return ParseUnits(s, BTOZ, RoundExact)
+}
+
+// ParseGasPrice parses s into grans. If s has no unit, it is in Ggrans.
+// It fails if s has digits below a gran.
+func ParseGasPrice(s string) (*uint256.Int, error) {
+	return ParseUnits(s, Ggrans, RoundExact)
+}
+
+// ParseUnitAmount converts the decimal number (e.g. "1.5") in unit into grans.
+// Unlike btztypes.ToGrans, which multiplies an integer of BTOZ, it takes a decimal string in any unit.
+func ParseUnitAmount(number string, unit Unit, mode RoundingMode) (*uint256.Int, error) {
+	intPart, fracPart, _ := strings.Cut(number, ".")
+	if intPart == "" && fracPart == "" {
+		return nil, fmt.Errorf("wrong number: %q", number)
+	}
+	for _, part := range []string{intPart, fracPart} {
+		if strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+			return nil, fmt.Errorf("wrong number: %q", number)
+		}
+	}
+
+	n, _ := new(big.Int).SetString("0"+intPart+fracPart, 10)
+	var v *big.Int
+	if shift := unit.Decimals - len(fracPart); shift >= 0 {
+		v = n.Mul(n, pow10(shift))
+	} else {
+		var err error
+		if v, err = roundQuo(n, pow10(-shift), mode); err != nil {
+			return nil, fmt.Errorf("%q %v: %w", number, unit.Name, err)
+		}
+	}
+
+	ret, overflow := uint256.FromBig(v)
+	if overflow {
+		return nil, fmt.Errorf("%q %v overflows", number, unit.Name)
+	}
+	return ret, nil
+}
+
+// FormatUnits formats grans in unit with at most maxDecimals fractional digits, without trailing zeros.
+// The digits beyond maxDecimals are rounded by mode. If maxDecimals is negative, all digits are kept.
+func FormatUnits(grans *uint256.Int, unit Unit, maxDecimals int, mode RoundingMode) (string, error) {
+	if maxDecimals < 0 || maxDecimals > unit.Decimals {
+		maxDecimals = unit.Decimals
+	}
+	v, err := roundQuo(grans.ToBig(), pow10(unit.Decimals-maxDecimals), mode)
+	if err != nil {
+		return "", err
+	}
+
+	digits := v.String()
+	if maxDecimals == 0 {
+		return digits, nil
+	}
+	if len(digits) <= maxDecimals {
+		digits = strings.Repeat("0", maxDecimals-len(digits)+1) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-maxDecimals], strings.TrimRight(digits[len(digits)-maxDecimals:], "0")
+	if fracPart == "" {
+		return intPart, nil
+	}
+	return intPart + "." + fracPart, nil
+}
+
+// FormatBTOZ formats grans in BTOZ with the unit name. (e.g. "1.5 BTOZ")
+func FormatBTOZ(grans *uint256.Int) string {
+	s, _ := FormatUnits(grans, BTOZ, -1, RoundExact)
+	return s + " " + BTOZ.Name
+}
+
+// FormatGasPrice formats grans in Ggrans with the unit name. (e.g. "250 Ggrans")
+func FormatGasPrice(grans *uint256.Int) string {
+	s, _ := FormatUnits(grans, Ggrans, -1, RoundExact)
+	return s + " " + Ggrans.Name
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundQuo returns n/d rounded by mode. n and d must not be negative.
+func roundQuo(n, d *big.Int, mode RoundingMode) (*big.Int, error) {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q, nil
+	}
+
+	roundUp := false
+	switch mode {
+	case RoundExact:
+		return nil, ErrInexact
+	case RoundDown:
+	case RoundUp:
+		roundUp = true
+	case RoundHalfUp:
+		roundUp = new(big.Int).Lsh(r, 1).Cmp(d) >= 0
+	case RoundHalfEven:
+		c := new(big.Int).Lsh(r, 1).Cmp(d)
+		roundUp = c > 0 || (c == 0 && q.Bit(0) == 1)
+	default:
+		return nil, fmt.Errorf("unknown rounding mode: %v", mode)
+	}
+	if roundUp {
+		q.Add(q, big.NewInt(1))
+	}
+	return q, nil
+}
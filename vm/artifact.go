@@ -0,0 +1,196 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// hardhatArtifact is also the format of truffle build files.
+type hardhatArtifact struct {
+	ABI                    json.RawMessage `json:"abi"`
+	Bytecode               json.RawMessage `json:"bytecode"`
+	DeployedBytecode       json.RawMessage `json:"deployedBytecode"`
+	LinkReferences         LinkReferences  `json:"linkReferences"`
+	DeployedLinkReferences LinkReferences  `json:"deployedLinkReferences"`
+}
+
+// foundryBytecode is the bytecode object of foundry artifacts in out/<File>.sol/<Contract>.json.
+type foundryBytecode struct {
+	Object         string         `json:"object"`
+	LinkReferences LinkReferences `json:"linkReferences"`
+}
+
+// unlinkedBytecode is a bytecode in hex which may have library placeholders.
+type unlinkedBytecode struct {
+	hex  string
+	refs LinkReferences
+}
+
+type solcCombinedJSON struct {
+	Contracts map[string]struct {
+		ABI        json.RawMessage `json:"abi"`
+		Bin        string          `json:"bin"`
+		BinRuntime string          `json:"bin-runtime"`
+	} `json:"contracts"`
+}
+
+// NewEVMContractWith creates a contract from an ABI JSON and its creation bytecode in memory.
+func NewEVMContractWith(abiJSON string, bytecode []byte) (*EVMContract, error) {
+	return newEVMContract([]byte(abiJSON), &unlinkedBytecode{hex: hex.EncodeToString(bytecode)}, nil)
+}
+
+// NewEVMContractWithHex creates a contract from an ABI JSON and its creation bytecode in hex,
+// which may have library placeholders to be linked by EVMContract.Link.
+func NewEVMContractWithHex(abiJSON, bytecode string) (*EVMContract, error) {
+	return newEVMContract([]byte(abiJSON), &unlinkedBytecode{hex: bytecode}, nil)
+}
+
+// NewEVMContractFromArtifact creates a contract from the content of a truffle, hardhat or foundry artifact.
+// The format is detected by the type of "bytecode", which is a string in truffle and hardhat
+// and an object in foundry.
+func NewEVMContractFromArtifact(bz []byte) (*EVMContract, error) {
+	art := hardhatArtifact{}
+	if err := json.Unmarshal(bz, &art); err != nil {
+		return nil, err
+	}
+	if len(art.ABI) == 0 {
+		return nil, errors.New("no abi in the artifact")
+	}
+
+	code, err := artifactBytecode(art.Bytecode, art.LinkReferences)
+	if err != nil {
+		return nil, fmt.Errorf("wrong bytecode: %w", err)
+	}
+	deployed, err := artifactBytecode(art.DeployedBytecode, art.DeployedLinkReferences)
+	if err != nil {
+		return nil, fmt.Errorf("wrong deployedBytecode: %w", err)
+	}
+	return newEVMContract(art.ABI, code, deployed)
+}
+
+// NewEVMContractFromHardhat creates a contract from a hardhat artifact file
+// in artifacts/contracts/<File>.sol/<Contract>.json.
+func NewEVMContractFromHardhat(path string) (*EVMContract, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMContractFromArtifact(bz)
+}
+
+// NewEVMContractFromFoundry creates a contract from a foundry artifact file in out/<File>.sol/<Contract>.json.
+func NewEVMContractFromFoundry(path string) (*EVMContract, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMContractFromArtifact(bz)
+}
+
+// NewEVMContractFromCombinedJSON creates the contract name from the output of `solc --combined-json abi,bin,bin-runtime`.
+// name is the contract name, or "<source>:<contract>" if the name is ambiguous.
+func NewEVMContractFromCombinedJSON(path, name string) (*EVMContract, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	out := solcCombinedJSON{}
+	if err := json.Unmarshal(bz, &out); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for key := range out.Contracts {
+		if key == name || strings.HasSuffix(key, ":"+name) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("contract %q not found in %v", name, path)
+	} else if len(keys) > 1 {
+		return nil, fmt.Errorf("contract %q is ambiguous: %v", name, strings.Join(keys, ", "))
+	}
+
+	c := out.Contracts[keys[0]]
+	abiJSON := []byte(c.ABI)
+	// solc before 0.8.10 writes the abi as a JSON string.
+	var s string
+	if err := json.Unmarshal(c.ABI, &s); err == nil {
+		abiJSON = []byte(s)
+	}
+
+	return newEVMContract(abiJSON,
+		&unlinkedBytecode{hex: c.Bin},
+		&unlinkedBytecode{hex: c.BinRuntime})
+}
+
+// NewEVMContractFromABIBin creates a contract from the .abi and .bin files written by `solc --abi --bin`.
+func NewEVMContractFromABIBin(abiPath, binPath string) (*EVMContract, error) {
+	abiJSON, err := os.ReadFile(abiPath)
+	if err != nil {
+		return nil, err
+	}
+	bin, err := os.ReadFile(binPath)
+	if err != nil {
+		return nil, err
+	}
+	return newEVMContract(abiJSON, &unlinkedBytecode{hex: string(bin)}, nil)
+}
+
+// newEVMContract creates a contract with the bytecodes.
+// If a bytecode has library placeholders, it is kept in hex until EVMContract.Link is called.
+func newEVMContract(abiJSON []byte, code, deployed *unlinkedBytecode) (*EVMContract, error) {
+	_abi, err := abi.JSON(bytes.NewReader(abiJSON))
+	if err != nil {
+		return nil, err
+	}
+	ret := &EVMContract{
+		buildInfo: truffleBuildInfo{ABI: abiJSON},
+		abi:       _abi,
+	}
+	if code != nil {
+		ret.unlinked = normalizeBytecode(code.hex)
+		ret.linkRefs = code.refs
+	}
+	if deployed != nil {
+		ret.unlinkedDeployed = normalizeBytecode(deployed.hex)
+		ret.deployedLinkRefs = deployed.refs
+	}
+	if err := ret.decodeLinked(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// artifactBytecode returns the bytecode of an artifact, which is a hex string or a foundry bytecode object.
+func artifactBytecode(raw json.RawMessage, refs LinkReferences) (*unlinkedBytecode, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		obj := foundryBytecode{}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, err
+		}
+		s, refs = obj.Object, obj.LinkReferences
+	}
+	return &unlinkedBytecode{hex: s, refs: refs}, nil
+}
+
+// normalizeBytecode strips 0x and white spaces from the hex string.
+func normalizeBytecode(s string) string {
+	s = strings.TrimSpace(s)
+	return strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+}
@@ -0,0 +1,224 @@
+// Package bind generates type-safe Go bindings of EVM contracts targeting web3.BeatozWeb3.
+package bind
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/beatoz/beatoz-sdk-go/vm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// reservedNames are the names used by the generated code,
+// so the arguments of the contract methods are renamed if they collide.
+var reservedNames = map[string]bool{
+	"w": true, "gas": true, "gasPrice": true, "amt": true, "bzweb3": true,
+	"from": true, "height": true, "fromHeight": true, "toHeight": true,
+	"out": true, "err": true, "ret": true, "logs": true, "log": true, "evt": true,
+	"abi": true, "big": true, "common": true, "errors": true, "uint256": true,
+	"vm": true, "web3": true, "btztypes": true, "coretypes": true,
+}
+
+// reservedMethods are the methods of the generated struct which contract methods can't be named as.
+var reservedMethods = map[string]bool{
+	"Contract": true, "Address": true, "Deploy": true,
+}
+
+type tmplArg struct {
+	Name string
+	Type string
+}
+
+type tmplMethod struct {
+	Name     string
+	RawName  string
+	Sig      string
+	Inputs   []tmplArg
+	Outputs  []tmplArg
+	Payable  bool
+	Constant bool
+}
+
+type tmplEvent struct {
+	Name    string
+	RawName string
+	Sig     string
+	Fields  []tmplArg
+	// Skipped is the reason the filter of the event is not generated.
+	Skipped string
+}
+
+type tmplData struct {
+	Package     string
+	Type        string
+	ABI         string
+	Bin         string
+	Constructor tmplMethod
+	Calls       []tmplMethod
+	Transacts   []tmplMethod
+	Events      []tmplEvent
+}
+
+// Generate returns the Go source of the binding of ec named typeName in the package pkg.
+func Generate(ec *vm.EVMContract, pkg, typeName string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("wrong package name: %q", pkg)
+	}
+	if !token.IsIdentifier(typeName) || !token.IsExported(typeName) {
+		return nil, fmt.Errorf("type name must be an exported identifier: %q", typeName)
+	}
+
+	contractABI := ec.GetABI()
+	data := &tmplData{
+		Package: pkg,
+		Type:    typeName,
+		ABI:     string(ec.GetABIJSON()),
+		Bin:     ec.GetBytecodeHex(),
+		Constructor: tmplMethod{
+			Inputs:  bindArgs(contractABI.Constructor.Inputs, "arg", map[string]bool{}),
+			Payable: contractABI.Constructor.IsPayable(),
+		},
+	}
+
+	for _, name := range sortedKeys(contractABI.Methods) {
+		m := contractABI.Methods[name]
+		// the outputs are declared in the same scope as the inputs.
+		taken := make(map[string]bool)
+		method := tmplMethod{
+			Name:     methodName(m.Name),
+			RawName:  m.Name,
+			Sig:      m.Sig,
+			Inputs:   bindArgs(m.Inputs, "arg", taken),
+			Outputs:  bindArgs(m.Outputs, "ret", taken),
+			Payable:  m.IsPayable(),
+			Constant: m.IsConstant(),
+		}
+		if method.Constant {
+			data.Calls = append(data.Calls, method)
+		} else {
+			data.Transacts = append(data.Transacts, method)
+		}
+	}
+
+	for _, name := range sortedKeys(contractABI.Events) {
+		e := contractABI.Events[name]
+		event := tmplEvent{
+			Name:    abi.ToCamelCase(e.Name),
+			RawName: e.Name,
+			Sig:     e.Sig,
+		}
+		if e.Anonymous {
+			event.Skipped = "it is anonymous"
+		}
+		// the abi package names unnamed arguments of events as arg0, arg1, ...
+		for _, arg := range e.Inputs {
+			if event.Skipped != "" {
+				break
+			}
+			field := abi.ToCamelCase(arg.Name)
+			switch {
+			case arg.Indexed && arg.Type.T == abi.TupleTy:
+				event.Skipped = "it has an indexed tuple"
+			case field == "Raw":
+				event.Skipped = "it has an argument named raw"
+			}
+
+			typ := goType(arg.Type)
+			if arg.Indexed && isHashedTopic(arg.Type) {
+				// only the hash of an indexed dynamic value is in the log.
+				typ = "common.Hash"
+			}
+			event.Fields = append(event.Fields, tmplArg{Name: field, Type: typ})
+		}
+		data.Events = append(data.Events, event)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := bindTemplate.Execute(buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("malformed binding: %w", err)
+	}
+	return src, nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func methodName(raw string) string {
+	name := abi.ToCamelCase(raw)
+	if reservedMethods[name] || strings.HasPrefix(name, "Filter") {
+		name += "_"
+	}
+	return name
+}
+
+// bindArgs names the arguments in lower camel case, and unnamed ones as prefix with their index.
+// A name already in taken is suffixed with "_" until it is unique, and then added to taken.
+func bindArgs(args abi.Arguments, prefix string, taken map[string]bool) []tmplArg {
+	var ret []tmplArg
+	for i, arg := range args {
+		name := fmt.Sprintf("%s%d", prefix, i)
+		if arg.Name != "" {
+			name = abi.ToCamelCase(arg.Name)
+			name = strings.ToLower(name[:1]) + name[1:]
+		}
+		if reservedNames[name] || token.IsKeyword(name) {
+			name += "_"
+		}
+		for taken[name] {
+			name += "_"
+		}
+		taken[name] = true
+		ret = append(ret, tmplArg{Name: name, Type: goType(arg.Type)})
+	}
+	return ret
+}
+
+// goType returns the Go type the abi package decodes t into.
+// Tuples are anonymous structs, e.g. struct { A *big.Int "json:\"a\"" }.
+func goType(t abi.Type) string {
+	return t.GetType().String()
+}
+
+func isHashedTopic(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy:
+		return true
+	}
+	return false
+}
+
+func argNames(args []tmplArg) string {
+	var names []string
+	for _, arg := range args {
+		names = append(names, arg.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func params(args []tmplArg) string {
+	var ret string
+	for _, arg := range args {
+		ret += ", " + arg.Name + " " + arg.Type
+	}
+	return ret
+}
+
+var bindTemplate = template.Must(template.New("bind").Funcs(template.FuncMap{
+	"argNames": argNames,
+	"params":   params,
+}).Parse(bindSource))
@@ -0,0 +1,122 @@
+package bind
+
+const bindSource = `// Code generated by beatoz-abigen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"errors"
+	"math/big"
+
+	btztypes "github.com/beatoz/beatoz-go/types"
+	"github.com/beatoz/beatoz-sdk-go/vm"
+	"github.com/beatoz/beatoz-sdk-go/web3"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = common.Big1
+	_ = abi.ConvertType
+	_ = uint256.NewInt
+)
+
+// {{.Type}}ABI is the ABI of {{.Type}}.
+const {{.Type}}ABI = {{printf "%q" .ABI}}
+
+// {{.Type}}Bin is the creation bytecode of {{.Type}} in hex.
+const {{.Type}}Bin = {{printf "%q" .Bin}}
+
+type {{.Type}} struct {
+	contract *vm.EVMContract
+}
+
+// New{{.Type}} binds the contract at addr.
+// If addr is nil, the contract has to be deployed by Deploy first.
+func New{{.Type}}(addr btztypes.Address) (*{{.Type}}, error) {
+	contract, err := vm.NewEVMContractWithHex({{.Type}}ABI, {{.Type}}Bin)
+	if err != nil {
+		return nil, err
+	}
+	contract.SetAddress(addr)
+	return &{{.Type}}{contract: contract}, nil
+}
+
+// Contract returns the underlying contract. Link its libraries before Deploy if it has any.
+func (_c *{{.Type}}) Contract() *vm.EVMContract {
+	return _c.contract
+}
+
+func (_c *{{.Type}}) Address() btztypes.Address {
+	return _c.contract.GetAddress()
+}
+
+// Deploy deploys the contract with the nonce of w and binds it to the deployed address.
+func (_c *{{.Type}}) Deploy(w *web3.Wallet, gas int64, gasPrice{{if .Constructor.Payable}}, amt{{end}} *uint256.Int, bzweb3 *web3.BeatozWeb3{{params .Constructor.Inputs}}) (*coretypes.ResultBroadcastTxCommit, error) {
+	return _c.contract.ExecCommit("", []interface{}{ {{argNames .Constructor.Inputs}} }, w, w.GetNonce(), gas, gasPrice, {{if .Constructor.Payable}}amt{{else}}uint256.NewInt(0){{end}}, bzweb3)
+}
+{{range .Calls}}
+// {{.Name}} calls {{.Sig}} at height. If height is 0, the latest state is used.
+func (_c *{{$.Type}}) {{.Name}}(from btztypes.Address, height int64, bzweb3 *web3.BeatozWeb3{{params .Inputs}}) ({{range .Outputs}}{{.Type}}, {{end}}error) {
+	{{- range .Outputs}}
+	var {{.Name}} {{.Type}}
+	{{- end}}
+
+	out, err := _c.contract.Call("{{.RawName}}", []interface{}{ {{argNames .Inputs}} }, from, height, bzweb3)
+	if err != nil {
+		return {{range .Outputs}}{{.Name}}, {{end}}err
+	}
+	if len(out) != {{len .Outputs}} {
+		return {{range .Outputs}}{{.Name}}, {{end}}errors.New("wrong number of return values")
+	}
+	{{- range $i, $out := .Outputs}}
+	{{$out.Name}} = *abi.ConvertType(out[{{$i}}], new({{$out.Type}})).(*{{$out.Type}})
+	{{- end}}
+	return {{range .Outputs}}{{.Name}}, {{end}}nil
+}
+{{end}}
+{{- range .Transacts}}
+// {{.Name}} sends a tx calling {{.Sig}} with the nonce of w and waits until it is committed.
+func (_c *{{$.Type}}) {{.Name}}(w *web3.Wallet, gas int64, gasPrice{{if .Payable}}, amt{{end}} *uint256.Int, bzweb3 *web3.BeatozWeb3{{params .Inputs}}) (*coretypes.ResultBroadcastTxCommit, error) {
+	return _c.contract.ExecCommit("{{.RawName}}", []interface{}{ {{argNames .Inputs}} }, w, w.GetNonce(), gas, gasPrice, {{if .Payable}}amt{{else}}uint256.NewInt(0){{end}}, bzweb3)
+}
+{{end}}
+{{- range .Events}}
+{{- if .Skipped}}
+// The filter of {{.Sig}} is not generated: {{.Skipped}}.
+{{else}}
+// {{$.Type}}{{.Name}} is the log of {{.Sig}}.
+type {{$.Type}}{{.Name}} struct {
+	{{- range .Fields}}
+	{{.Name}} {{.Type}}
+	{{- end}}
+	Raw *vm.EVMLog
+}
+
+// Filter{{.Name}} returns the logs of {{.Sig}} emitted from fromHeight to toHeight.
+// If toHeight is 0, the logs up to the latest height are returned.
+func (_c *{{$.Type}}) Filter{{.Name}}(fromHeight, toHeight int64, bzweb3 *web3.BeatozWeb3) ([]*{{$.Type}}{{.Name}}, error) {
+	logs, err := _c.contract.FilterLogs("{{.RawName}}", fromHeight, toHeight, bzweb3)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*{{$.Type}}{{.Name}}
+	for _, log := range logs {
+		evt := new({{$.Type}}{{.Name}})
+		if err := _c.contract.UnpackLog(evt, "{{.RawName}}", log); err != nil {
+			return nil, err
+		}
+		evt.Raw = log
+		ret = append(ret, evt)
+	}
+	return ret, nil
+}
+{{end}}
+{{- end}}
+`
@@ -1,7 +1,7 @@
 package vm
 
 import (
-	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"os"
@@ -27,27 +27,21 @@ type EVMContract struct {
 	buildInfo truffleBuildInfo
 	abi       abi.ABI
 	addr      types.Address
+
+	// unlinked and unlinkedDeployed are the bytecodes in hex until their libraries are linked.
+	unlinked         string
+	unlinkedDeployed string
+	linkRefs         LinkReferences
+	deployedLinkRefs LinkReferences
 }
 
+// NewEVMContract creates a contract from a truffle, hardhat or foundry artifact file.
 func NewEVMContract(path string) (*EVMContract, error) {
 	bz, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-
-	bi := truffleBuildInfo{}
-	if err := json.Unmarshal(bz, &bi); err != nil {
-		return nil, err
-	}
-
-	_abi, err := abi.JSON(bytes.NewReader(bi.ABI))
-	if err != nil {
-		return nil, err
-	}
-	return &EVMContract{
-		buildInfo: bi,
-		abi:       _abi,
-	}, nil
+	return NewEVMContractFromArtifact(bz)
 }
 
 func (ec *EVMContract) SetAddress(addr types.Address) {
@@ -58,6 +52,23 @@ func (ec *EVMContract) GetAddress() types.Address {
 	return ec.addr
 }
 
+func (ec *EVMContract) GetABI() abi.ABI {
+	return ec.abi
+}
+
+func (ec *EVMContract) GetABIJSON() []byte {
+	return ec.buildInfo.ABI
+}
+
+// GetBytecodeHex returns the creation bytecode in hex without 0x.
+// Unlike GetBytecode, it returns the bytecode even if it has unlinked library placeholders.
+func (ec *EVMContract) GetBytecodeHex() string {
+	if ec.unlinked != "" {
+		return ec.unlinked
+	}
+	return hex.EncodeToString(ec.buildInfo.Bytecode)
+}
+
 func (ec *EVMContract) GetBytecode() rbytes.HexBytes {
 	return rbytes.HexBytes(ec.buildInfo.Bytecode)
 }
@@ -107,7 +118,9 @@ func (ec *EVMContract) ExecAsync(name string, args []interface{}, from *web3.Wal
 	if name == "" {
 		// constructor
 		to = types.ZeroAddress()
-		data = append(ec.buildInfo.Bytecode, data...)
+		if data, err = ec.creationData(data); err != nil {
+			return nil, err
+		}
 	}
 	tx := web3.NewTrxContract(from.Address(), to, nonce, gas, gasPrice, amt, data)
 	_, _, err = from.SignTrxRLP(tx, bzweb3.ChainID())
@@ -134,7 +147,9 @@ func (ec *EVMContract) ExecSync(name string, args []interface{}, from *web3.Wall
 	if name == "" {
 		// constructor
 		to = types.ZeroAddress()
-		data = append(ec.buildInfo.Bytecode, data...)
+		if data, err = ec.creationData(data); err != nil {
+			return nil, err
+		}
 	}
 	tx := web3.NewTrxContract(from.Address(), to, nonce, gas, gasPrice, amt, data)
 	_, _, err = from.SignTrxRLP(tx, bzweb3.ChainID())
@@ -166,7 +181,9 @@ func (ec *EVMContract) ExecCommit(name string, args []interface{}, from *web3.Wa
 	if name == "" {
 		// constructor
 		to = types.ZeroAddress()
-		data = append(ec.buildInfo.Bytecode, data...)
+		if data, err = ec.creationData(data); err != nil {
+			return nil, err
+		}
 	}
 	tx := web3.NewTrxContract(from.Address(), to, nonce, gas, gasPrice, amt, data)
 	_, _, err = from.SignTrxRLP(tx, bzweb3.ChainID())
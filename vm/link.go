@@ -0,0 +1,214 @@
+package vm
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beatoz/beatoz-go/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// placeholderLen is the length of a library placeholder in hex,
+// which is replaced with the 20 bytes address of the library.
+const placeholderLen = 40
+
+// LinkReference is the position of a library address in a bytecode, in bytes.
+type LinkReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// LinkReferences maps a source file to the libraries defined in it and their positions in a bytecode,
+// as in the "linkReferences" of hardhat and foundry artifacts.
+type LinkReferences map[string]map[string][]LinkReference
+
+// LibraryPlaceholder returns the placeholder solc (>= 0.5.0) puts in a bytecode for the library
+// whose fully qualified name is "<source>:<library>".
+func LibraryPlaceholder(fqName string) string {
+	hash := hex.EncodeToString(ethcrypto.Keccak256([]byte(fqName)))
+	return "__$" + hash[:34] + "$__"
+}
+
+// Link replaces the placeholders of the libraries in libs with their addresses.
+// A key of libs is the library name or its fully qualified name "<source>:<library>".
+// The placeholders are found by the link references of the artifact,
+// or by the hash or the name of the library in them if the artifact has no link references.
+// The hash placeholders of solc >= 0.5.0 can be found only by the fully qualified name,
+// so combined-json and abi/bin inputs, which have no link references, need it as the key.
+// Link returns an error without linking anything if a key of libs matches no placeholder.
+// Link can be called several times until UnlinkedLibraries is empty.
+func (ec *EVMContract) Link(libs map[string]types.Address) error {
+	matched := make(map[string]bool)
+	unlinked, err := linkBytecode(ec.unlinked, ec.linkRefs, libs, matched)
+	if err != nil {
+		return fmt.Errorf("bytecode: %w", err)
+	}
+	unlinkedDeployed, err := linkBytecode(ec.unlinkedDeployed, ec.deployedLinkRefs, libs, matched)
+	if err != nil {
+		return fmt.Errorf("deployedBytecode: %w", err)
+	}
+
+	var unmatched []string
+	for name := range libs {
+		if !matched[name] {
+			unmatched = append(unmatched, name)
+		}
+	}
+	if len(unmatched) > 0 {
+		sort.Strings(unmatched)
+		return fmt.Errorf("no placeholder of %v in the bytecode, use the fully qualified name <source>:<library> if it is a bare name",
+			strings.Join(unmatched, ", "))
+	}
+
+	ec.unlinked, ec.unlinkedDeployed = unlinked, unlinkedDeployed
+	return ec.decodeLinked()
+}
+
+// LinkReferences returns the link references of the creation bytecode.
+func (ec *EVMContract) LinkReferences() LinkReferences {
+	return ec.linkRefs
+}
+
+// UnlinkedLibraries returns the names of the libraries whose placeholders remain in the creation bytecode.
+// A library whose name is unknown is returned as its placeholder.
+func (ec *EVMContract) UnlinkedLibraries() []string {
+	names := make(map[string]string)
+	for source, libRefs := range ec.linkRefs {
+		for lib := range libRefs {
+			fqName := source + ":" + lib
+			names[LibraryPlaceholder(fqName)] = fqName
+		}
+	}
+
+	found := make(map[string]bool)
+	for _, ph := range findPlaceholders(ec.unlinked) {
+		if name, ok := names[ph]; ok {
+			found[name] = true
+		} else if !strings.HasPrefix(ph, "__$") {
+			found[strings.TrimRight(ph[2:], "_")] = true
+		} else {
+			found[ph] = true
+		}
+	}
+
+	var ret []string
+	for name := range found {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (ec *EVMContract) decodeLinked() error {
+	if ec.unlinked != "" && !strings.Contains(ec.unlinked, "__") {
+		bz, err := hex.DecodeString(ec.unlinked)
+		if err != nil {
+			return fmt.Errorf("wrong bytecode: %w", err)
+		}
+		ec.buildInfo.Bytecode = bz
+		ec.unlinked = ""
+	}
+	if ec.unlinkedDeployed != "" && !strings.Contains(ec.unlinkedDeployed, "__") {
+		bz, err := hex.DecodeString(ec.unlinkedDeployed)
+		if err != nil {
+			return fmt.Errorf("wrong deployedBytecode: %w", err)
+		}
+		ec.buildInfo.DeployedBytecode = bz
+		ec.unlinkedDeployed = ""
+	}
+	return nil
+}
+
+// creationData returns the creation bytecode followed by the packed constructor args.
+func (ec *EVMContract) creationData(args []byte) ([]byte, error) {
+	if ec.unlinked != "" {
+		return nil, fmt.Errorf("bytecode has unlinked libraries: %v", strings.Join(ec.UnlinkedLibraries(), ", "))
+	}
+	data := make([]byte, 0, len(ec.buildInfo.Bytecode)+len(args))
+	data = append(data, ec.buildInfo.Bytecode...)
+	return append(data, args...), nil
+}
+
+// linkBytecode replaces the placeholders of libs in code and adds the keys of libs it used to matched.
+func linkBytecode(code string, refs LinkReferences, libs map[string]types.Address, matched map[string]bool) (string, error) {
+	if code == "" || len(libs) == 0 {
+		return code, nil
+	}
+
+	bz := []byte(code)
+	for source, libRefs := range refs {
+		for lib, positions := range libRefs {
+			key := source + ":" + lib
+			addr, ok := libs[key]
+			if !ok {
+				key = lib
+				addr, ok = libs[key]
+			}
+			if !ok {
+				continue
+			}
+			matched[key] = true
+			if len(addr) != 20 {
+				return "", fmt.Errorf("wrong address of %v: %v", lib, addr)
+			}
+			for _, pos := range positions {
+				start, end := pos.Start*2, (pos.Start+pos.Length)*2
+				if pos.Length != 20 || start < 0 || end > len(bz) {
+					return "", fmt.Errorf("wrong link reference of %v: %+v", lib, pos)
+				}
+				copy(bz[start:end], hex.EncodeToString(addr))
+			}
+		}
+	}
+
+	code = string(bz)
+	for _, ph := range findPlaceholders(code) {
+		for name, addr := range libs {
+			if !matchPlaceholder(ph, name) {
+				continue
+			}
+			if len(addr) != 20 {
+				return "", fmt.Errorf("wrong address of %v: %v", name, addr)
+			}
+			matched[name] = true
+			code = strings.ReplaceAll(code, ph, hex.EncodeToString(addr))
+			break
+		}
+	}
+	return code, nil
+}
+
+// findPlaceholders returns the distinct placeholders in code.
+func findPlaceholders(code string) []string {
+	var ret []string
+	seen := make(map[string]bool)
+	for i := 0; ; i += placeholderLen {
+		j := strings.Index(code[i:], "__")
+		if j < 0 || i+j+placeholderLen > len(code) {
+			break
+		}
+		i += j
+		ph := code[i : i+placeholderLen]
+		if !seen[ph] {
+			seen[ph] = true
+			ret = append(ret, ph)
+		}
+	}
+	return ret
+}
+
+// matchPlaceholder returns true if ph is the placeholder of the library name.
+// solc >= 0.5.0 puts the hash of the fully qualified name in it, and older versions put the name itself.
+func matchPlaceholder(ph, name string) bool {
+	if strings.HasPrefix(ph, "__$") {
+		return ph == LibraryPlaceholder(name)
+	}
+
+	phName := strings.TrimRight(ph[2:], "_")
+	if len(name) > placeholderLen-4 {
+		name = name[:placeholderLen-4]
+	}
+	return phName == name || strings.HasSuffix(phName, ":"+name)
+}
@@ -0,0 +1,134 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	btztypes "github.com/beatoz/beatoz-go/types"
+	rbytes "github.com/beatoz/beatoz-go/types/bytes"
+	"github.com/beatoz/beatoz-sdk-go/types"
+	"github.com/beatoz/beatoz-sdk-go/web3"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EVMLog is an EVM log emitted by a tx, which the node stores as an "evm" event of the tx.
+type EVMLog struct {
+	Address btztypes.Address
+	Topics  []common.Hash
+	Data    []byte
+	Height  int64
+	TxHash  rbytes.HexBytes
+}
+
+// EVMLogsOf returns the EVM logs emitted by the tx.
+func EVMLogsOf(txRet *types.TrxResult) ([]*EVMLog, error) {
+	var ret []*EVMLog
+	for _, evt := range txRet.TxResult.Events {
+		if evt.Type != "evm" {
+			continue
+		}
+
+		log := &EVMLog{
+			Height: txRet.Height,
+			TxHash: rbytes.HexBytes(txRet.Hash),
+		}
+		for _, attr := range evt.Attributes {
+			key, val := string(attr.Key), string(attr.Value)
+			switch {
+			case key == "contractAddress":
+				bz, err := hex.DecodeString(val)
+				if err != nil {
+					return nil, fmt.Errorf("wrong contract address of evm log: %w", err)
+				}
+				log.Address = bz
+			case strings.HasPrefix(key, "topic."):
+				idx, err := strconv.Atoi(strings.TrimPrefix(key, "topic."))
+				if err != nil {
+					return nil, fmt.Errorf("wrong topic key of evm log: %v", key)
+				}
+				bz, err := hex.DecodeString(val)
+				if err != nil {
+					return nil, fmt.Errorf("wrong topic of evm log: %w", err)
+				}
+				for len(log.Topics) <= idx {
+					log.Topics = append(log.Topics, common.Hash{})
+				}
+				log.Topics[idx] = common.BytesToHash(bz)
+			case key == "data":
+				bz, err := hex.DecodeString(val)
+				if err != nil {
+					return nil, fmt.Errorf("wrong data of evm log: %w", err)
+				}
+				log.Data = bz
+			}
+		}
+		ret = append(ret, log)
+	}
+	return ret, nil
+}
+
+// FilterLogs returns the logs of the event emitted by the contract from fromHeight to toHeight.
+// If toHeight is 0, the logs up to the latest height are returned.
+// The txs are found by tx_search with the event signature, so the node must index txs.
+func (ec *EVMContract) FilterLogs(event string, fromHeight, toHeight int64, bzweb3 *web3.BeatozWeb3) ([]*EVMLog, error) {
+	if ec.addr == nil {
+		return nil, errors.New("no contract address")
+	}
+	evt, ok := ec.abi.Events[event]
+	if !ok {
+		return nil, fmt.Errorf("event %q not found", event)
+	}
+	topic0 := strings.ToUpper(hex.EncodeToString(evt.ID.Bytes()))
+
+	query := types.NewTxQuery().
+		Attr("evm", "topic.0", topic0).
+		HeightRange(fromHeight, toHeight)
+	txs, err := bzweb3.AllTxSearch(query, types.OrderAsc)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*EVMLog
+	for _, txRet := range txs {
+		logs, err := EVMLogsOf(txRet)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			if len(log.Topics) > 0 && log.Topics[0] == evt.ID && bytes.Equal(log.Address, ec.addr) {
+				ret = append(ret, log)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// UnpackLog unpacks the log of the event into out, a pointer to a struct
+// whose fields are named as the arguments of the event in camel case.
+func (ec *EVMContract) UnpackLog(out interface{}, event string, log *EVMLog) error {
+	evt, ok := ec.abi.Events[event]
+	if !ok {
+		return fmt.Errorf("event %q not found", event)
+	}
+	if len(log.Topics) == 0 || log.Topics[0] != evt.ID {
+		return fmt.Errorf("the log is not of the event %q", event)
+	}
+
+	if len(log.Data) > 0 {
+		if err := ec.abi.UnpackIntoInterface(out, event, log.Data); err != nil {
+			return err
+		}
+	}
+	var indexed abi.Arguments
+	for _, arg := range evt.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	return abi.ParseTopics(out, indexed, log.Topics[1:])
+}
@@ -2,31 +2,50 @@ package web3
 
 import (
 	"github.com/beatoz/beatoz-sdk-go/types"
+	"log/slog"
 	"sync"
 )
 
 type BeatozWeb3 struct {
 	chainId  string
 	provider types.Provider
+	logger   *slog.Logger
 	callId   int64
 	mtx      sync.RWMutex
 }
 
-func NewBeatozWeb3(provider types.Provider) *BeatozWeb3 {
+func NewBeatozWeb3(provider types.Provider, opts ...func(*BeatozWeb3)) *BeatozWeb3 {
 	types.NewRequest(0, "genesis")
 
 	bzweb3 := &BeatozWeb3{
 		provider: provider,
+		logger:   discardLogger(),
 	}
+	for _, cb := range opts {
+		cb(bzweb3)
+	}
+
 	gen, err := bzweb3.Genesis()
 	if err != nil {
 		panic(err)
-		return nil
 	}
 	bzweb3.chainId = gen.Genesis.ChainID
+	bzweb3.logger.Debug("beatoz web3 created", "chainId", bzweb3.chainId)
 	return bzweb3
 }
 
+// WithWeb3Logger makes BeatozWeb3 log every RPC call to logger.
+// If redactTx is true, signed tx bytes in requests and responses are not logged.
+func WithWeb3Logger(logger *slog.Logger, redactTx bool) func(*BeatozWeb3) {
+	return func(bzweb3 *BeatozWeb3) {
+		if logger == nil {
+			return
+		}
+		bzweb3.logger = logger
+		bzweb3.provider = types.Chain(bzweb3.provider, LogMiddleware(logger, redactTx))
+	}
+}
+
 func (bzweb3 *BeatozWeb3) ChainID() string {
 	bzweb3.mtx.RLock()
 	defer bzweb3.mtx.RUnlock()
@@ -41,6 +60,10 @@ func (bzweb3 *BeatozWeb3) SetChainID(cid string) {
 	bzweb3.chainId = cid
 }
 
+func (bzweb3 *BeatozWeb3) Logger() *slog.Logger {
+	return bzweb3.logger
+}
+
 func (bzweb3 *BeatozWeb3) NewRequest(method string, args ...interface{}) (*types.JSONRpcReq, error) {
 	bzweb3.mtx.Lock()
 	defer bzweb3.mtx.Unlock()
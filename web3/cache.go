@@ -0,0 +1,266 @@
+package web3
+
+import (
+	"container/list"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beatoz/beatoz-go/rpc"
+	"github.com/beatoz/beatoz-sdk-go/types"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// DefaultHeightParamIndex maps the methods whose result is immutable for a given height
+// to the position of the height in their params.
+// A call is cached only when that height is greater than 0, since 0 means the latest height,
+// and not higher than the latest height of the node, since a higher one isn't final yet.
+var DefaultHeightParamIndex = map[string]int{
+	"gov_params":          0,
+	"account":             1,
+	"delegatee":           1,
+	"stakes":              1,
+	"reward":              1,
+	"stakes/total_power":  0,
+	"stakes/voting_power": 0,
+	"proposal":            1,
+	"validators":          0,
+	"vm_call":             2,
+	"block":               0,
+	"block_results":       0,
+	"consensus_params":    0,
+}
+
+// DefaultQueryRoutes lists the methods which return the result of an ABCI query.
+// Their failures come in a successful response with a non-zero code, so they are cached only when the code is 0.
+var DefaultQueryRoutes = map[string]bool{
+	"gov_params":          true,
+	"account":             true,
+	"delegatee":           true,
+	"stakes":              true,
+	"reward":              true,
+	"stakes/total_power":  true,
+	"stakes/voting_power": true,
+	"proposal":            true,
+	"vm_call":             true,
+}
+
+// DefaultImmutableMethods lists the methods whose result never changes, regardless of params.
+var DefaultImmutableMethods = map[string]bool{
+	"genesis": true,
+}
+
+type CachePolicy struct {
+	// Size is the maximum number of cached responses. If it is 0 or less, 1024 is used.
+	Size int
+	// TTL is how long a response stays cached. If it is 0 or less, responses don't expire.
+	TTL time.Duration
+	// HeightParamIndex overrides DefaultHeightParamIndex if it is not nil.
+	HeightParamIndex map[string]int
+	// ImmutableMethods overrides DefaultImmutableMethods if it is not nil.
+	ImmutableMethods map[string]bool
+	// QueryRoutes overrides DefaultQueryRoutes if it is not nil.
+	QueryRoutes map[string]bool
+}
+
+type cacheEntry struct {
+	key     string
+	resp    *types.JSONRpcResp
+	expires time.Time
+}
+
+// CachingProvider caches the responses of height-pinned queries and genesis in an LRU cache.
+// Error responses and failed queries are never cached.
+type CachingProvider struct {
+	next   types.Provider
+	policy CachePolicy
+	// lastHeight is the latest height of the node the provider has seen.
+	lastHeight int64
+
+	entries map[string]*list.Element
+	lru     *list.List
+	mtx     sync.Mutex
+}
+
+var _ types.Provider = (*CachingProvider)(nil)
+
+func NewCachingProvider(next types.Provider, policy CachePolicy) *CachingProvider {
+	if policy.Size <= 0 {
+		policy.Size = 1024
+	}
+	if policy.HeightParamIndex == nil {
+		policy.HeightParamIndex = DefaultHeightParamIndex
+	}
+	if policy.ImmutableMethods == nil {
+		policy.ImmutableMethods = DefaultImmutableMethods
+	}
+	if policy.QueryRoutes == nil {
+		policy.QueryRoutes = DefaultQueryRoutes
+	}
+	return &CachingProvider{
+		next:    next,
+		policy:  policy,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func CacheMiddleware(policy CachePolicy) types.Middleware {
+	return func(next types.Provider) types.Provider {
+		return NewCachingProvider(next, policy)
+	}
+}
+
+func (cp *CachingProvider) Call(req *types.JSONRpcReq) (*types.JSONRpcResp, error) {
+	if !cp.cacheable(req) {
+		resp, err := cp.next.Call(req)
+		if err == nil && req.Method == "status" {
+			cp.observeStatus(resp)
+		}
+		return resp, err
+	}
+
+	key := req.Method + string(req.Params)
+	if resp := cp.get(key); resp != nil {
+		ret := *resp
+		ret.Id = req.Id
+		return &ret, nil
+	}
+
+	resp, err := cp.next.Call(req)
+	if err == nil && resp != nil && resp.Error == nil && cp.succeeded(req.Method, resp) {
+		cp.put(key, resp)
+	}
+	return resp, err
+}
+
+// succeeded returns false if resp is the result of a failed query.
+func (cp *CachingProvider) succeeded(method string, resp *types.JSONRpcResp) bool {
+	if !cp.policy.QueryRoutes[method] {
+		return true
+	}
+	queryResp := &rpc.QueryResult{}
+	if err := tmjson.Unmarshal(resp.Result, queryResp); err != nil {
+		return false
+	}
+	return queryResp.Code == 0
+}
+
+func (cp *CachingProvider) cacheable(req *types.JSONRpcReq) bool {
+	if cp.policy.ImmutableMethods[req.Method] {
+		return true
+	}
+	idx, ok := cp.policy.HeightParamIndex[req.Method]
+	if !ok {
+		return false
+	}
+
+	var params []json.RawMessage
+	if err := json.Unmarshal(req.Params, &params); err != nil || idx >= len(params) {
+		return false
+	}
+	height, err := strconv.ParseInt(strings.Trim(string(params[idx]), `"`), 10, 64)
+	if err != nil || height <= 0 {
+		return false
+	}
+	return cp.isFinal(height)
+}
+
+// isFinal returns true if height is not higher than the latest height of the node.
+// It asks the node for its status only when height is higher than the last one seen.
+func (cp *CachingProvider) isFinal(height int64) bool {
+	cp.mtx.Lock()
+	last := cp.lastHeight
+	cp.mtx.Unlock()
+	if height <= last {
+		return true
+	}
+
+	req, err := types.NewRequest(0, "status")
+	if err != nil {
+		return false
+	}
+	resp, err := cp.next.Call(req)
+	if err != nil {
+		return false
+	}
+	return height <= cp.observeStatus(resp)
+}
+
+// observeStatus updates lastHeight with the status in resp and returns it.
+func (cp *CachingProvider) observeStatus(resp *types.JSONRpcResp) int64 {
+	cp.mtx.Lock()
+	defer cp.mtx.Unlock()
+
+	status := &coretypes.ResultStatus{}
+	if resp != nil && resp.Error == nil && tmjson.Unmarshal(resp.Result, status) == nil &&
+		status.SyncInfo.LatestBlockHeight > cp.lastHeight {
+		cp.lastHeight = status.SyncInfo.LatestBlockHeight
+	}
+	return cp.lastHeight
+}
+
+func (cp *CachingProvider) get(key string) *types.JSONRpcResp {
+	cp.mtx.Lock()
+	defer cp.mtx.Unlock()
+
+	elem, ok := cp.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		cp.lru.Remove(elem)
+		delete(cp.entries, key)
+		return nil
+	}
+	cp.lru.MoveToFront(elem)
+	return entry.resp
+}
+
+func (cp *CachingProvider) put(key string, resp *types.JSONRpcResp) {
+	cp.mtx.Lock()
+	defer cp.mtx.Unlock()
+
+	var expires time.Time
+	if cp.policy.TTL > 0 {
+		expires = time.Now().Add(cp.policy.TTL)
+	}
+
+	if elem, ok := cp.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.resp = resp
+		entry.expires = expires
+		cp.lru.MoveToFront(elem)
+		return
+	}
+
+	cp.entries[key] = cp.lru.PushFront(&cacheEntry{
+		key:     key,
+		resp:    resp,
+		expires: expires,
+	})
+	for cp.lru.Len() > cp.policy.Size {
+		oldest := cp.lru.Back()
+		cp.lru.Remove(oldest)
+		delete(cp.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (cp *CachingProvider) Len() int {
+	cp.mtx.Lock()
+	defer cp.mtx.Unlock()
+
+	return cp.lru.Len()
+}
+
+func (cp *CachingProvider) Purge() {
+	cp.mtx.Lock()
+	defer cp.mtx.Unlock()
+
+	cp.entries = make(map[string]*list.Element)
+	cp.lru.Init()
+}
@@ -0,0 +1,230 @@
+package web3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	btztypes "github.com/beatoz/beatoz-go/types"
+	"github.com/holiman/uint256"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+type CompoundPolicy struct {
+	// Delegatee is the validator the withdrawn reward is staked to.
+	Delegatee btztypes.Address
+	// Threshold is the minimum reward to compound.
+	// Rewards less than the minimum stake of Delegatee are never compounded, regardless of Threshold.
+	Threshold *uint256.Int
+	// Gas and GasPrice are used for both the withdraw and the staking tx.
+	// If they are zero, the minimum tx gas and the gas price of the governance parameters are used.
+	Gas      int64
+	GasPrice *uint256.Int
+}
+
+type CompoundResult struct {
+	Height int64 `json:"height"`
+	// Reward is the reward not withdrawn yet when the compounding started.
+	Reward *uint256.Int `json:"reward"`
+	// Skipped is true if Reward didn't reach the threshold and no tx was sent.
+	Skipped    bool                               `json:"skipped"`
+	Withdrawn  *uint256.Int                       `json:"withdrawn,omitempty"`
+	Staked     *uint256.Int                       `json:"staked,omitempty"`
+	WithdrawTx *coretypes.ResultBroadcastTxCommit `json:"withdrawTx,omitempty"`
+	StakeTx    *coretypes.ResultBroadcastTxCommit `json:"stakeTx,omitempty"`
+}
+
+// Compound withdraws the reward of w and stakes it to policy.Delegatee if the reward exceeds policy.Threshold.
+// Each tx is committed before the next one is sent, and the nonce and balance of w are synced before each tx.
+// The staked amount is the withdrawn reward rounded down to a multiple of the amount per power,
+// and less if the balance can't pay the fee of the staking tx too.
+func (w *Wallet) Compound(policy *CompoundPolicy, bzweb3 *BeatozWeb3) (*CompoundResult, error) {
+	if policy.Delegatee == nil {
+		return nil, errors.New("no delegatee to stake to")
+	}
+
+	govParams, err := bzweb3.QueryGovParams()
+	if err != nil {
+		return nil, err
+	}
+	gas, gasPrice := policy.Gas, policy.GasPrice
+	if gas <= 0 {
+		gas = govParams.MinTrxGas()
+	}
+	if gasPrice == nil {
+		gasPrice = govParams.GasPrice()
+	}
+	fee := btztypes.GasToFee(gas, gasPrice)
+
+	minPower := govParams.MinDelegatorPower()
+	if bytes.Equal(policy.Delegatee, w.Address()) {
+		minPower = 1
+	}
+
+	rwd, err := bzweb3.QueryReward(w.Address(), 0)
+	if err != nil {
+		return nil, err
+	}
+	reward := rwd.Cumulated.Int()
+
+	ret := &CompoundResult{Height: rwd.Height, Reward: reward}
+	if (policy.Threshold != nil && reward.Lt(policy.Threshold)) ||
+		reward.Lt(btztypes.PowerToAmount(minPower)) {
+		ret.Skipped = true
+		return ret, nil
+	}
+
+	if err := w.SyncAccount(bzweb3); err != nil {
+		return nil, err
+	}
+	if w.GetBalance().Lt(fee) {
+		return nil, fmt.Errorf("not enough balance to pay the withdraw fee: %v < %v", w.GetBalance().Dec(), fee.Dec())
+	}
+	withdrawTx, err := w.WithdrawCommit(gas, gasPrice, reward, bzweb3)
+	if err != nil {
+		return nil, err
+	}
+	ret.WithdrawTx = withdrawTx
+	if err := commitError("withdraw", withdrawTx); err != nil {
+		return ret, err
+	}
+	ret.Withdrawn = reward
+
+	if err := w.SyncAccount(bzweb3); err != nil {
+		return ret, err
+	}
+	amt := new(uint256.Int)
+	if bal := w.GetBalance(); !bal.Lt(fee) {
+		_ = amt.Sub(bal, fee)
+	}
+	if reward.Lt(amt) {
+		amt.Set(reward)
+	}
+	_ = amt.Sub(amt, new(uint256.Int).Mod(amt, btztypes.AmountPerPower()))
+	if amt.Lt(btztypes.PowerToAmount(minPower)) {
+		return ret, fmt.Errorf("reward withdrawn but too small to stake: %v", amt.Dec())
+	}
+
+	stakeTx, err := w.StakingCommit(policy.Delegatee, gas, gasPrice, amt, bzweb3)
+	if err != nil {
+		return ret, err
+	}
+	ret.StakeTx = stakeTx
+	if err := commitError("staking", stakeTx); err != nil {
+		return ret, err
+	}
+	ret.Staked = amt
+	return ret, w.SyncAccount(bzweb3)
+}
+
+func commitError(name string, ret *coretypes.ResultBroadcastTxCommit) error {
+	if ret.CheckTx.Code != 0 {
+		return fmt.Errorf("%s tx failed on check: %s", name, ret.CheckTx.Log)
+	}
+	if ret.DeliverTx.Code != 0 {
+		return fmt.Errorf("%s tx failed on deliver: %s", name, ret.DeliverTx.Log)
+	}
+	return nil
+}
+
+// Compounder runs Wallet.Compound every interval.
+type Compounder struct {
+	wallet   *Wallet
+	bzweb3   *BeatozWeb3
+	policy   *CompoundPolicy
+	interval time.Duration
+	handler  func(*CompoundResult, error)
+	logger   *slog.Logger
+
+	done chan struct{}
+	mtx  sync.Mutex
+}
+
+func NewCompounder(w *Wallet, bzweb3 *BeatozWeb3, policy *CompoundPolicy, interval time.Duration, opts ...func(*Compounder)) *Compounder {
+	ret := &Compounder{
+		wallet:   w,
+		bzweb3:   bzweb3,
+		policy:   policy,
+		interval: interval,
+		logger:   discardLogger(),
+	}
+	for _, cb := range opts {
+		cb(ret)
+	}
+	return ret
+}
+
+// WithCompoundHandler sets the function called with the result of every run.
+func WithCompoundHandler(handler func(*CompoundResult, error)) func(*Compounder) {
+	return func(c *Compounder) {
+		c.handler = handler
+	}
+}
+
+func WithCompounderLogger(logger *slog.Logger) func(*Compounder) {
+	return func(c *Compounder) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// RunOnce compounds the reward once.
+// It must not run concurrently with other txs of the wallet, since they share the nonce.
+func (c *Compounder) RunOnce() (*CompoundResult, error) {
+	ret, err := c.wallet.Compound(c.policy, c.bzweb3)
+	if err != nil {
+		c.logger.Warn("compounding failed", "address", c.wallet.Address(), "err", err)
+	} else if ret.Skipped {
+		c.logger.Debug("compounding skipped", "address", c.wallet.Address(), "reward", ret.Reward.Dec())
+	} else {
+		c.logger.Info("compounded", "address", c.wallet.Address(), "withdrawn", ret.Withdrawn.Dec(), "staked", ret.Staked.Dec())
+	}
+	if c.handler != nil {
+		c.handler(ret, err)
+	}
+	return ret, err
+}
+
+func (c *Compounder) Start() error {
+	if c.interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.done != nil {
+		return errors.New("compounder is already started")
+	}
+	done := make(chan struct{})
+	c.done = done
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_, _ = c.RunOnce()
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *Compounder) Stop() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.done != nil {
+		close(c.done)
+		c.done = nil
+	}
+}
@@ -0,0 +1,182 @@
+package web3
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/beatoz/beatoz-go/ctrlers/gov/proposal"
+	"github.com/beatoz/beatoz-go/rpc"
+	btztypes "github.com/beatoz/beatoz-go/types"
+	btzbytes "github.com/beatoz/beatoz-go/types/bytes"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+)
+
+// The statuses of proposals returned by the node.
+// A proposal is "voting" until its voting period ends and "frozen" until it is applied.
+// Applied or rejected proposals are removed from the state.
+const (
+	ProposalStatusVoting = "voting"
+	ProposalStatusFrozen = "frozen"
+)
+
+// QueryProposals returns all the proposals in the governance state at height.
+func (bzweb3 *BeatozWeb3) QueryProposals(height int64) ([]*QueryProposalResult, error) {
+	var ret []*QueryProposalResult
+	queryResp := &rpc.QueryResult{}
+	if req, err := bzweb3.NewRequest("proposal", "", strconv.FormatInt(height, 10)); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, queryResp); err != nil {
+		return nil, err
+	} else if queryResp.Code != 0 {
+		return nil, errors.New(queryResp.Log)
+	} else if err := tmjson.Unmarshal(queryResp.Value, &ret); err != nil {
+		return nil, err
+	} else {
+		return ret, nil
+	}
+}
+
+// QueryProposalsByStatus returns the proposals at height whose status is one of statuses.
+func (bzweb3 *BeatozWeb3) QueryProposalsByStatus(height int64, statuses ...string) ([]*QueryProposalResult, error) {
+	props, err := bzweb3.QueryProposals(height)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*QueryProposalResult
+	for _, prop := range props {
+		for _, status := range statuses {
+			if prop.Status == status {
+				ret = append(ret, prop)
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+type OptionTally struct {
+	Option []byte `json:"option"`
+	Votes  int64  `json:"votes"`
+}
+
+type ProposalTally struct {
+	TxHash            btzbytes.HexBytes `json:"txHash"`
+	Status            string            `json:"status"`
+	PropType          int32             `json:"propType"`
+	StartVotingHeight int64             `json:"startVotingHeight"`
+	EndVotingHeight   int64             `json:"endVotingHeight"`
+	ApplyHeight       int64             `json:"applyHeight"`
+	// TotalVotingPower is the voting power of all voters when the proposal was submitted.
+	TotalVotingPower int64 `json:"totalVotingPower"`
+	// MajorityPower is the votes an option needs to be accepted.
+	MajorityPower int64 `json:"majorityPower"`
+	// VotedPower is the voting power of the voters who have voted.
+	VotedPower int64 `json:"votedPower"`
+	// CurrentVotingPower is the voting power of the validators at the queried height.
+	CurrentVotingPower int64          `json:"currentVotingPower"`
+	Options            []*OptionTally `json:"options"`
+	// MajorOption is the option whose votes reached MajorityPower, or nil.
+	MajorOption *OptionTally `json:"majorOption,omitempty"`
+}
+
+func (tally *ProposalTally) IsPassed() bool {
+	return tally.MajorOption != nil
+}
+
+// RemainingVotingBlocks returns how many blocks are left in the voting period at height.
+func (tally *ProposalTally) RemainingVotingBlocks(height int64) int64 {
+	if height >= tally.EndVotingHeight {
+		return 0
+	}
+	return tally.EndVotingHeight - height
+}
+
+// QueryProposalTally returns the current votes of the proposal at height.
+func (bzweb3 *BeatozWeb3) QueryProposalTally(txhash []byte, height int64) (*ProposalTally, error) {
+	prop, err := bzweb3.QueryProposal(txhash, height)
+	if err != nil {
+		return nil, err
+	}
+	if prop.Proposal == nil {
+		return nil, errors.New("proposal not found")
+	}
+
+	currPower, err := bzweb3.QueryVotingPower(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProposalTally(prop, currPower), nil
+}
+
+func newProposalTally(prop *QueryProposalResult, currPower int64) *ProposalTally {
+	header := prop.Proposal.Header()
+
+	ret := &ProposalTally{
+		TxHash:             header.GetTxHash(),
+		Status:             prop.Status,
+		PropType:           header.GetPropType(),
+		StartVotingHeight:  header.GetStartVotingHeight(),
+		EndVotingHeight:    header.GetEndVotingHeight(),
+		ApplyHeight:        header.GetApplyHeight(),
+		TotalVotingPower:   header.GetTotalVotingPower(),
+		MajorityPower:      header.GetMajorityPower(),
+		CurrentVotingPower: currPower,
+	}
+	for _, voter := range header.GetVoters() {
+		if voter.GetChoice() != proposal.NOT_CHOICE {
+			ret.VotedPower += voter.GetPower()
+		}
+	}
+	for _, opt := range prop.Proposal.Options() {
+		ret.Options = append(ret.Options, &OptionTally{
+			Option: opt.GetOption(),
+			Votes:  opt.GetVotes(),
+		})
+	}
+	if major := prop.Proposal.MajorOption(); major != nil {
+		ret.MajorOption = &OptionTally{
+			Option: major.GetOption(),
+			Votes:  major.GetVotes(),
+		}
+	}
+	return ret
+}
+
+type VoterChoice struct {
+	Address btztypes.Address `json:"address"`
+	Power   int64            `json:"power"`
+	// Choice is the index of the chosen option, or proposal.NOT_CHOICE if the voter has not voted.
+	Choice int32 `json:"choice"`
+}
+
+func (vc *VoterChoice) HasVoted() bool {
+	return vc.Choice != proposal.NOT_CHOICE
+}
+
+// QueryVoterChoice returns the choice of addr on the proposal at height.
+// It returns nil if addr is not a voter of the proposal.
+func (bzweb3 *BeatozWeb3) QueryVoterChoice(txhash []byte, addr btztypes.Address, height int64) (*VoterChoice, error) {
+	prop, err := bzweb3.QueryProposal(txhash, height)
+	if err != nil {
+		return nil, err
+	}
+	if prop.Proposal == nil {
+		return nil, errors.New("proposal not found")
+	}
+
+	voter := prop.Proposal.FindVoter(addr)
+	if voter == nil {
+		return nil, nil
+	}
+	return &VoterChoice{
+		Address: voter.GetAddress(),
+		Power:   voter.GetPower(),
+		Choice:  voter.GetChoice(),
+	}, nil
+}
@@ -0,0 +1,129 @@
+package web3
+
+import (
+	"time"
+
+	"github.com/beatoz/beatoz-sdk-go/types"
+)
+
+// CallInfo describes one provider call.
+// The same CallInfo is passed to BeforeCall and AfterCall of every hook,
+// so a hook can keep per-call state in it with SetValue.
+type CallInfo struct {
+	Method    string
+	Id        int64
+	Start     time.Time
+	Duration  time.Duration
+	ReqBytes  int
+	RespBytes int
+	// Err is the error returned by the provider, e.g. a transport error.
+	Err error
+	// RpcError is the JSON-RPC error in the response, if any.
+	RpcError *types.JSONRpcError
+
+	values map[interface{}]interface{}
+}
+
+func (info *CallInfo) SetValue(key, value interface{}) {
+	if info.values == nil {
+		info.values = make(map[interface{}]interface{})
+	}
+	info.values[key] = value
+}
+
+func (info *CallInfo) Value(key interface{}) interface{} {
+	return info.values[key]
+}
+
+// Failed reports whether the call failed with a transport error or a JSON-RPC error.
+func (info *CallInfo) Failed() bool {
+	return info.Err != nil || info.RpcError != nil
+}
+
+type CallHook interface {
+	// BeforeCall is called before the request is sent. Duration, RespBytes and errors are not set yet.
+	BeforeCall(info *CallInfo)
+	// AfterCall is called after the provider returns.
+	AfterCall(info *CallInfo)
+}
+
+// HookMiddleware calls the hooks around every call.
+// BeforeCall is called in the given order and AfterCall in the reverse order.
+func HookMiddleware(hooks ...CallHook) types.Middleware {
+	return func(next types.Provider) types.Provider {
+		return types.ProviderFunc(func(req *types.JSONRpcReq) (*types.JSONRpcResp, error) {
+			info := &CallInfo{
+				Method:   req.Method,
+				Id:       req.Id,
+				Start:    time.Now(),
+				ReqBytes: len(req.Params),
+			}
+			for _, h := range hooks {
+				h.BeforeCall(info)
+			}
+
+			resp, err := next.Call(req)
+
+			info.Duration = time.Since(info.Start)
+			info.Err = err
+			if resp != nil {
+				info.RespBytes = len(resp.Result) + len(resp.Error)
+				info.RpcError = resp.RpcError()
+			}
+			for i := len(hooks) - 1; i >= 0; i-- {
+				hooks[i].AfterCall(info)
+			}
+			return resp, err
+		})
+	}
+}
+
+// Span is the subset of an OpenTelemetry span used by TracingHook.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts a span for each call. Wrap an OpenTelemetry tracer to implement it.
+type Tracer interface {
+	StartSpan(name string) Span
+}
+
+type tracingHookKey struct{}
+
+// TracingHook starts a span named "beatoz.rpc/<method>" for every call.
+type TracingHook struct {
+	tracer Tracer
+}
+
+var _ CallHook = (*TracingHook)(nil)
+
+func NewTracingHook(tracer Tracer) *TracingHook {
+	return &TracingHook{tracer: tracer}
+}
+
+func (h *TracingHook) BeforeCall(info *CallInfo) {
+	span := h.tracer.StartSpan("beatoz.rpc/" + info.Method)
+	span.SetAttribute("rpc.system", "jsonrpc")
+	span.SetAttribute("rpc.method", info.Method)
+	span.SetAttribute("rpc.jsonrpc.request_id", info.Id)
+	info.SetValue(tracingHookKey{}, span)
+}
+
+func (h *TracingHook) AfterCall(info *CallInfo) {
+	span, ok := info.Value(tracingHookKey{}).(Span)
+	if !ok {
+		return
+	}
+	span.SetAttribute("rpc.request.size", info.ReqBytes)
+	span.SetAttribute("rpc.response.size", info.RespBytes)
+	if info.Err != nil {
+		span.RecordError(info.Err)
+	}
+	if info.RpcError != nil {
+		span.SetAttribute("rpc.jsonrpc.error_code", info.RpcError.Code)
+		span.SetAttribute("rpc.jsonrpc.error_message", info.RpcError.Message)
+	}
+	span.End()
+}
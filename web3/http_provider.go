@@ -2,21 +2,28 @@ package web3
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/beatoz/beatoz-sdk-go/types"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type HttpProvider struct {
-	url string
+	url      string
+	logger   *slog.Logger
+	redactTx bool
 	//httpClient *http.Client
 }
 
 func NewHttpProvider(url string, opts ...func(*HttpProvider)) *HttpProvider {
 	ret := &HttpProvider{
-		url: url,
+		url:    url,
+		logger: discardLogger(),
 		//httpClient: &http.Client{
 		//	//Timeout: time.Second * time.Duration(10), // for [connect ~ request ~ response] time
 		//	Transport: &http.Transport{
@@ -33,6 +40,17 @@ func NewHttpProvider(url string, opts ...func(*HttpProvider)) *HttpProvider {
 	return ret
 }
 
+// WithHttpProviderLogger makes HttpProvider log every HTTP round trip to logger.
+// If redactTx is true, request and response bodies carrying signed tx bytes are not logged.
+func WithHttpProviderLogger(logger *slog.Logger, redactTx bool) func(*HttpProvider) {
+	return func(client *HttpProvider) {
+		if logger != nil {
+			client.logger = logger
+			client.redactTx = redactTx
+		}
+	}
+}
+
 func (client *HttpProvider) Call(req *types.JSONRpcReq) (*types.JSONRpcResp, error) {
 	// Lock removed - http.Post is goroutine-safe and can handle concurrent calls
 	reqbz, err := json.Marshal(req)
@@ -40,9 +58,17 @@ func (client *HttpProvider) Call(req *types.JSONRpcReq) (*types.JSONRpcResp, err
 		return nil, err
 	}
 
+	ctx := context.Background()
+	redact := client.redactTx && hasTxBytes(req.Method)
+	if client.logger.Enabled(ctx, LevelTrace) {
+		client.logger.Log(ctx, LevelTrace, "http request", "url", client.url, "method", req.Method, "id", req.Id, "body", logPayload(reqbz, redact))
+	}
+
+	start := time.Now()
 	httpBody := bytes.NewBuffer(reqbz)
 	httpResp, err := http.Post(client.url, "application/json", httpBody)
 	if err != nil {
+		client.logger.Warn("http request failed", "url", client.url, "method", req.Method, "id", req.Id, "err", err)
 		return nil, err
 	}
 
@@ -51,17 +77,59 @@ func (client *HttpProvider) Call(req *types.JSONRpcReq) (*types.JSONRpcResp, err
 	}()
 
 	if httpResp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Bad HTTP Response: %v", httpResp.Status)
+		client.logger.Warn("bad http response", "url", client.url, "method", req.Method, "id", req.Id, "status", httpResp.Status)
+		return nil, &HttpStatusError{
+			StatusCode: httpResp.StatusCode,
+			Status:     httpResp.Status,
+			RetryAfter: parseRetryAfter(httpResp.Header.Get("Retry-After")),
+		}
 	}
 
 	respBody, err := ioutil.ReadAll(httpResp.Body)
 	if err != nil {
+		client.logger.Warn("http response read failed", "url", client.url, "method", req.Method, "id", req.Id, "err", err)
 		return nil, err
 	}
 
+	client.logger.Debug("http response", "url", client.url, "method", req.Method, "id", req.Id,
+		"elapsed", time.Since(start), "reqBytes", len(reqbz), "respBytes", len(respBody))
+	if client.logger.Enabled(ctx, LevelTrace) {
+		client.logger.Log(ctx, LevelTrace, "http response body", "url", client.url, "method", req.Method, "id", req.Id, "body", logPayload(respBody, redact))
+	}
+
 	res := &types.JSONRpcResp{}
 	if err = json.Unmarshal(respBody, res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
+
+// HttpStatusError is returned by HttpProvider.Call when the response status is not 200 OK.
+type HttpStatusError struct {
+	StatusCode int
+	Status     string
+	// RetryAfter is the delay requested by the "Retry-After" header, or 0 if there is none.
+	RetryAfter time.Duration
+}
+
+func (e *HttpStatusError) Error() string {
+	return fmt.Sprintf("Bad HTTP Response: %v", e.Status)
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
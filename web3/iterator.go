@@ -0,0 +1,168 @@
+package web3
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/beatoz/beatoz-sdk-go/types"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// DefaultPerPage is the page size used by the iterators. It is the maximum allowed by the node.
+const DefaultPerPage = 100
+
+// PageIterator iterates over the items of a paged endpoint, fetching the next page when needed.
+//
+//	it := bzweb3.IterValidators(0, 0)
+//	for it.Next() {
+//		val := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type PageIterator[T any] struct {
+	fetch func(page int) ([]T, int, error)
+
+	page  int
+	items []T
+	idx   int
+	seen  int
+	total int
+	last  bool
+	cur   T
+	err   error
+}
+
+// NewPageIterator returns an iterator over the pages returned by fetch.
+// fetch returns the items of the page (starting from 1) and the total count of items.
+func NewPageIterator[T any](fetch func(page int) ([]T, int, error)) *PageIterator[T] {
+	return &PageIterator[T]{fetch: fetch}
+}
+
+func (it *PageIterator[T]) Next() bool {
+	for it.idx >= len(it.items) {
+		if it.last || it.err != nil {
+			return false
+		}
+
+		items, total, err := it.fetch(it.page + 1)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page++
+		it.items = items
+		it.idx = 0
+		it.total = total
+		it.seen += len(items)
+		it.last = len(items) == 0 || it.seen >= total
+	}
+
+	it.cur = it.items[it.idx]
+	it.idx++
+	return true
+}
+
+func (it *PageIterator[T]) Value() T {
+	return it.cur
+}
+
+func (it *PageIterator[T]) Err() error {
+	return it.err
+}
+
+// Total returns the total count reported by the last fetched page.
+func (it *PageIterator[T]) Total() int {
+	return it.total
+}
+
+// All collects the remaining items.
+func (it *PageIterator[T]) All() ([]T, error) {
+	var ret []T
+	for it.Next() {
+		ret = append(ret, it.Value())
+	}
+	return ret, it.Err()
+}
+
+// IterValidators iterates over the validators at height.
+// If height is 0, the height of the first page is used for the following pages,
+// so that all pages belong to the same validator set.
+func (bzweb3 *BeatozWeb3) IterValidators(height int64, perPage int) *PageIterator[*tmtypes.Validator] {
+	if perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+	return NewPageIterator(func(page int) ([]*tmtypes.Validator, int, error) {
+		ret, err := bzweb3.QueryValidators(height, page, perPage)
+		if err != nil {
+			return nil, 0, err
+		}
+		height = ret.BlockHeight
+		return ret.Validators, ret.Total, nil
+	})
+}
+
+func (bzweb3 *BeatozWeb3) AllValidators(height int64) ([]*tmtypes.Validator, error) {
+	return bzweb3.IterValidators(height, DefaultPerPage).All()
+}
+
+func (bzweb3 *BeatozWeb3) IterTxSearch(query *types.TxQuery, perPage int, orderBy string) *PageIterator[*types.TrxResult] {
+	if perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+	return NewPageIterator(func(page int) ([]*types.TrxResult, int, error) {
+		ret, err := bzweb3.QueryTxSearch(query, page, perPage, orderBy)
+		if err != nil {
+			return nil, 0, err
+		}
+		return ret.Txs, ret.TotalCount, nil
+	})
+}
+
+func (bzweb3 *BeatozWeb3) AllTxSearch(query *types.TxQuery, orderBy string) ([]*types.TrxResult, error) {
+	return bzweb3.IterTxSearch(query, DefaultPerPage, orderBy).All()
+}
+
+// QueryBlockSearch returns a page of the blocks matched by the begin/end block event query.
+// page starts from 1, and orderBy is types.OrderAsc or types.OrderDesc (by height).
+func (bzweb3 *BeatozWeb3) QueryBlockSearch(query string, page, perPage int, orderBy string) (*coretypes.ResultBlockSearch, error) {
+	ret := &coretypes.ResultBlockSearch{}
+
+	if page == 0 {
+		page = 1
+	}
+	if orderBy == "" {
+		orderBy = types.OrderAsc
+	}
+	_page := strconv.Itoa(page)
+	_perPage := strconv.Itoa(perPage)
+
+	if req, err := bzweb3.NewRequest("block_search", query, _page, _perPage, orderBy); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (bzweb3 *BeatozWeb3) IterBlockSearch(query string, perPage int, orderBy string) *PageIterator[*coretypes.ResultBlock] {
+	if perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+	return NewPageIterator(func(page int) ([]*coretypes.ResultBlock, int, error) {
+		ret, err := bzweb3.QueryBlockSearch(query, page, perPage, orderBy)
+		if err != nil {
+			return nil, 0, err
+		}
+		return ret.Blocks, ret.TotalCount, nil
+	})
+}
+
+func (bzweb3 *BeatozWeb3) AllBlockSearch(query string, orderBy string) ([]*coretypes.ResultBlock, error) {
+	return bzweb3.IterBlockSearch(query, DefaultPerPage, orderBy).All()
+}
@@ -0,0 +1,86 @@
+package web3
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/beatoz/beatoz-sdk-go/types"
+)
+
+// LevelTrace is more verbose than slog.LevelDebug.
+// Request params and response results are logged at this level.
+const LevelTrace = slog.LevelDebug - 4
+
+// txBytesMethods lists the methods whose params or results carry signed tx bytes.
+var txBytesMethods = map[string]bool{
+	"tx":              true,
+	"tx_search":       true,
+	"check_tx":        true,
+	"unconfirmed_txs": true,
+	"block":           true,
+	"block_by_hash":   true,
+	"block_search":    true,
+}
+
+func hasTxBytes(method string) bool {
+	return strings.HasPrefix(method, "broadcast_tx_") || txBytesMethods[method]
+}
+
+// LogMiddleware logs every call at debug level and its params and result at LevelTrace.
+// If redactTx is true, the params and results of methods carrying signed tx bytes are not logged.
+func LogMiddleware(logger *slog.Logger, redactTx bool) types.Middleware {
+	if logger == nil {
+		logger = discardLogger()
+	}
+
+	return func(next types.Provider) types.Provider {
+		return types.ProviderFunc(func(req *types.JSONRpcReq) (*types.JSONRpcResp, error) {
+			ctx := context.Background()
+			redact := redactTx && hasTxBytes(req.Method)
+
+			if logger.Enabled(ctx, LevelTrace) {
+				logger.Log(ctx, LevelTrace, "rpc request", "method", req.Method, "id", req.Id, "params", logPayload(req.Params, redact))
+			}
+
+			start := time.Now()
+			resp, err := next.Call(req)
+			elapsed := time.Since(start)
+
+			if err != nil {
+				logger.Warn("rpc call failed", "method", req.Method, "id", req.Id, "elapsed", elapsed, "err", err)
+				return resp, err
+			}
+
+			if rpcErr := resp.RpcError(); rpcErr != nil {
+				logger.Debug("rpc error response", "method", req.Method, "id", req.Id, "elapsed", elapsed,
+					"code", rpcErr.Code, "message", rpcErr.Message, "data", rpcErr.Data)
+			} else {
+				logger.Debug("rpc response", "method", req.Method, "id", req.Id, "elapsed", elapsed, "bytes", len(resp.Result))
+			}
+			if logger.Enabled(ctx, LevelTrace) {
+				logger.Log(ctx, LevelTrace, "rpc response", "method", req.Method, "id", req.Id, "result", logPayload(resp.Result, redact))
+			}
+			return resp, err
+		})
+	}
+}
+
+func logPayload(bz []byte, redact bool) slog.Value {
+	if redact {
+		return slog.StringValue("<redacted>")
+	}
+	return slog.StringValue(string(bz))
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+func discardLogger() *slog.Logger {
+	return slog.New(discardHandler{})
+}
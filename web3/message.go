@@ -0,0 +1,185 @@
+package web3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	btztypes "github.com/beatoz/beatoz-go/types"
+	btzbytes "github.com/beatoz/beatoz-go/types/bytes"
+	"github.com/beatoz/beatoz-go/types/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// The personal message prefix intentionally omits the chain id
+// so that a signed message can never be mistaken for a transaction preimage,
+// which is prefixed with "\x19BEATOZ(<chainId>) Signed Message:\n".
+const personalMessagePrefix = "\x19BEATOZ Signed Message:\n%d"
+
+func GetPreimagePersonalMessage(msg []byte) []byte {
+	prefix := fmt.Sprintf(personalMessagePrefix, len(msg))
+	return append([]byte(prefix), msg...)
+}
+
+func (w *Wallet) SignMessage(msg []byte) (btzbytes.HexBytes, error) {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	return w.wkey.Sign(GetPreimagePersonalMessage(msg))
+}
+
+func VerifyMessage(addr btztypes.Address, msg, sig []byte) (bool, error) {
+	return verifyPreimage(addr, GetPreimagePersonalMessage(msg), sig)
+}
+
+func RecoverMessageSigner(msg, sig []byte) (btztypes.Address, error) {
+	addr, _, xerr := crypto.Sig2Addr(GetPreimagePersonalMessage(msg), sig)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return addr, nil
+}
+
+// TypedDataDomain is the EIP-712 style domain for Beatoz.
+// ChainId is the Beatoz chain id string (e.g. the genesis chain_id) instead of a numeric chain id.
+type TypedDataDomain struct {
+	Name              string `json:"name"`
+	Version           string `json:"version"`
+	ChainId           string `json:"chainId"`
+	VerifyingContract string `json:"verifyingContract,omitempty"`
+}
+
+func NewTypedDataDomain(name, version string, bzweb3 *BeatozWeb3) TypedDataDomain {
+	return TypedDataDomain{
+		Name:    name,
+		Version: version,
+		ChainId: bzweb3.ChainID(),
+	}
+}
+
+func (domain *TypedDataDomain) types() []apitypes.Type {
+	ret := []apitypes.Type{
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "string"},
+	}
+	if domain.VerifyingContract != "" {
+		ret = append(ret, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	return ret
+}
+
+func (domain *TypedDataDomain) toMap() apitypes.TypedDataMessage {
+	ret := apitypes.TypedDataMessage{
+		"name":    domain.Name,
+		"version": domain.Version,
+		"chainId": domain.ChainId,
+	}
+	if domain.VerifyingContract != "" {
+		ret["verifyingContract"] = domain.VerifyingContract
+	}
+	return ret
+}
+
+type TypedData struct {
+	Types       apitypes.Types            `json:"types"`
+	PrimaryType string                    `json:"primaryType"`
+	Domain      TypedDataDomain           `json:"domain"`
+	Message     apitypes.TypedDataMessage `json:"message"`
+}
+
+func NewTypedData(domain TypedDataDomain, primaryType string, types apitypes.Types, message apitypes.TypedDataMessage) *TypedData {
+	return &TypedData{
+		Types:       types,
+		PrimaryType: primaryType,
+		Domain:      domain,
+		Message:     message,
+	}
+}
+
+// Preimage returns "\x19\x01" || domainSeparator || hashStruct(message).
+func (td *TypedData) Preimage() ([]byte, error) {
+	if td.PrimaryType == "" {
+		return nil, errors.New("typed data has no primary type")
+	}
+	if td.PrimaryType == "EIP712Domain" {
+		return nil, errors.New("primary type must not be EIP712Domain")
+	}
+	if td.Domain.ChainId == "" {
+		return nil, errors.New("typed data domain has no chain id")
+	}
+
+	_types := make(apitypes.Types, len(td.Types)+1)
+	for k, v := range td.Types {
+		_types[k] = v
+	}
+	_types["EIP712Domain"] = td.Domain.types()
+
+	ethTd := &apitypes.TypedData{
+		Types:       _types,
+		PrimaryType: td.PrimaryType,
+		// apitypes only checks that the domain is not empty.
+		// the domain separator itself is computed from td.Domain below.
+		Domain: apitypes.TypedDataDomain{
+			Name:    td.Domain.Name,
+			Version: td.Domain.Version,
+			Salt:    td.Domain.ChainId,
+		},
+		Message: td.Message,
+	}
+
+	domainSeparator, err := ethTd.HashStruct("EIP712Domain", td.Domain.toMap())
+	if err != nil {
+		return nil, err
+	}
+	msgHash, err := ethTd.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	preimg := make([]byte, 0, 2+len(domainSeparator)+len(msgHash))
+	preimg = append(preimg, 0x19, 0x01)
+	preimg = append(preimg, domainSeparator...)
+	preimg = append(preimg, msgHash...)
+	return preimg, nil
+}
+
+func (w *Wallet) SignTypedData(td *TypedData) (btzbytes.HexBytes, error) {
+	preimg, err := td.Preimage()
+	if err != nil {
+		return nil, err
+	}
+
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	return w.wkey.Sign(preimg)
+}
+
+func VerifyTypedData(addr btztypes.Address, td *TypedData, sig []byte) (bool, error) {
+	preimg, err := td.Preimage()
+	if err != nil {
+		return false, err
+	}
+	return verifyPreimage(addr, preimg, sig)
+}
+
+func RecoverTypedDataSigner(td *TypedData, sig []byte) (btztypes.Address, error) {
+	preimg, err := td.Preimage()
+	if err != nil {
+		return nil, err
+	}
+	addr, _, xerr := crypto.Sig2Addr(preimg, sig)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return addr, nil
+}
+
+func verifyPreimage(addr btztypes.Address, preimg, sig []byte) (bool, error) {
+	signer, _, xerr := crypto.Sig2Addr(preimg, sig)
+	if xerr != nil {
+		return false, xerr
+	}
+	return bytes.Equal(signer, addr), nil
+}
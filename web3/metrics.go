@@ -0,0 +1,80 @@
+package web3
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusHook records the latency, size and errors of every call as Prometheus metrics.
+type PrometheusHook struct {
+	duration  *prometheus.HistogramVec
+	reqBytes  *prometheus.CounterVec
+	respBytes *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+}
+
+var _ CallHook = (*PrometheusHook)(nil)
+
+// NewPrometheusHook creates the metrics under namespace (e.g. "beatoz_sdk") and registers them to reg.
+// If reg is nil, prometheus.DefaultRegisterer is used.
+// If any of them fails to be registered, none of them stays registered.
+func NewPrometheusHook(namespace string, reg prometheus.Registerer) (*PrometheusHook, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	ret := &PrometheusHook{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "call_duration_seconds",
+			Help:      "Latency of JSON-RPC calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		reqBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "request_bytes_total",
+			Help:      "Size of JSON-RPC request params.",
+		}, []string{"method"}),
+		respBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "response_bytes_total",
+			Help:      "Size of JSON-RPC response results and errors.",
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "errors_total",
+			Help:      "Failed JSON-RPC calls by error code. Transport errors have the code \"transport\".",
+		}, []string{"method", "code"}),
+	}
+
+	collectors := []prometheus.Collector{ret.duration, ret.reqBytes, ret.respBytes, ret.errors}
+	for i, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			// unregister the ones already registered so that a retry doesn't fail with AlreadyRegisteredError.
+			for _, registered := range collectors[:i] {
+				reg.Unregister(registered)
+			}
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (h *PrometheusHook) BeforeCall(info *CallInfo) {}
+
+func (h *PrometheusHook) AfterCall(info *CallInfo) {
+	h.duration.WithLabelValues(info.Method).Observe(info.Duration.Seconds())
+	h.reqBytes.WithLabelValues(info.Method).Add(float64(info.ReqBytes))
+	h.respBytes.WithLabelValues(info.Method).Add(float64(info.RespBytes))
+
+	if info.Err != nil {
+		h.errors.WithLabelValues(info.Method, "transport").Inc()
+	} else if info.RpcError != nil {
+		h.errors.WithLabelValues(info.Method, strconv.Itoa(info.RpcError.Code)).Inc()
+	}
+}
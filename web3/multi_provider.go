@@ -0,0 +1,316 @@
+package web3
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/beatoz/beatoz-sdk-go/types"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+type SelectStrategy int
+
+const (
+	// SelectFailover always uses the first healthy provider in the given order.
+	SelectFailover SelectStrategy = iota
+	// SelectRoundRobin rotates over the healthy providers.
+	SelectRoundRobin
+	// SelectLowestLatency prefers the healthy provider with the lowest health check latency.
+	SelectLowestLatency
+)
+
+const (
+	DefaultHealthCheckInterval = 10 * time.Second
+	DefaultMaxHeightLag        = int64(5)
+)
+
+type providerNode struct {
+	provider types.Provider
+	healthy  bool
+	height   int64
+	latency  time.Duration
+	lastErr  error
+}
+
+// MultiProvider wraps several providers and dispatches every call to one of them.
+// Providers that fail with a transport error, that are catching up,
+// or whose latest_block_height is more than maxHeightLag behind the highest one are evicted
+// until the next health check finds them healthy again.
+// Health checks run only after Start is called, so an evicted provider never comes back without it.
+type MultiProvider struct {
+	nodes        []*providerNode
+	strategy     SelectStrategy
+	maxHeightLag int64
+	maxRetries   int
+	interval     time.Duration
+	next         int
+
+	done chan struct{}
+	mtx  sync.RWMutex
+}
+
+var _ types.Provider = (*MultiProvider)(nil)
+
+func NewFailoverProvider(providers []types.Provider, opts ...func(*MultiProvider)) *MultiProvider {
+	return NewMultiProvider(SelectFailover, providers, opts...)
+}
+
+func NewLoadBalancedProvider(strategy SelectStrategy, providers []types.Provider, opts ...func(*MultiProvider)) *MultiProvider {
+	return NewMultiProvider(strategy, providers, opts...)
+}
+
+func NewMultiProvider(strategy SelectStrategy, providers []types.Provider, opts ...func(*MultiProvider)) *MultiProvider {
+	ret := &MultiProvider{
+		strategy:     strategy,
+		maxHeightLag: DefaultMaxHeightLag,
+		maxRetries:   len(providers),
+		interval:     DefaultHealthCheckInterval,
+	}
+	for _, p := range providers {
+		ret.nodes = append(ret.nodes, &providerNode{
+			provider: p,
+			healthy:  true,
+		})
+	}
+
+	for _, cb := range opts {
+		cb(ret)
+	}
+	return ret
+}
+
+func WithHealthCheckInterval(d time.Duration) func(*MultiProvider) {
+	return func(mp *MultiProvider) {
+		mp.interval = d
+	}
+}
+
+func WithMaxHeightLag(lag int64) func(*MultiProvider) {
+	return func(mp *MultiProvider) {
+		mp.maxHeightLag = lag
+	}
+}
+
+// WithMaxRetries sets how many providers are tried for one call.
+// The default is the number of providers.
+func WithMaxRetries(n int) func(*MultiProvider) {
+	return func(mp *MultiProvider) {
+		mp.maxRetries = n
+	}
+}
+
+// Call sends req to the first candidate provider and fails over to the next one on a transport error.
+// Only the methods IsIdempotentMethod reports are failed over. The others, e.g. broadcasting methods and "check_tx",
+// are sent to one provider only, since the failed one may have accepted the tx
+// and the next one would reject it as a duplicate or with an invalid nonce.
+func (mp *MultiProvider) Call(req *types.JSONRpcReq) (*types.JSONRpcResp, error) {
+	candidates := mp.candidates()
+	if len(candidates) == 0 {
+		return nil, errors.New("no provider")
+	}
+
+	retries := mp.maxRetries
+	if retries <= 0 || retries > len(candidates) {
+		retries = len(candidates)
+	}
+	if !IsIdempotentMethod(req.Method) {
+		retries = 1
+	}
+
+	var lastErr error
+	for _, node := range candidates[:retries] {
+		resp, err := node.provider.Call(req)
+		if err != nil {
+			mp.evict(node, err)
+			lastErr = err
+			continue
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("all providers failed: %w", lastErr)
+}
+
+// candidates returns the healthy providers ordered by the selection strategy.
+// If no provider is healthy, all providers are returned so that a call is still attempted.
+func (mp *MultiProvider) candidates() []*providerNode {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	var ret []*providerNode
+	for _, node := range mp.nodes {
+		if node.healthy {
+			ret = append(ret, node)
+		}
+	}
+	if len(ret) == 0 {
+		ret = append(ret, mp.nodes...)
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+
+	switch mp.strategy {
+	case SelectRoundRobin:
+		start := mp.next % len(ret)
+		mp.next++
+		rotated := make([]*providerNode, 0, len(ret))
+		rotated = append(rotated, ret[start:]...)
+		ret = append(rotated, ret[:start]...)
+	case SelectLowestLatency:
+		sort.SliceStable(ret, func(i, j int) bool {
+			return ret[i].latency < ret[j].latency
+		})
+	}
+	return ret
+}
+
+func (mp *MultiProvider) evict(node *providerNode, err error) {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	node.healthy = false
+	node.lastErr = err
+}
+
+// CheckHealth calls "status" on every provider and updates their health, height and latency.
+func (mp *MultiProvider) CheckHealth() {
+	mp.mtx.RLock()
+	nodes := make([]*providerNode, len(mp.nodes))
+	copy(nodes, mp.nodes)
+	mp.mtx.RUnlock()
+
+	type checkResult struct {
+		height  int64
+		latency time.Duration
+		err     error
+	}
+	results := make([]checkResult, len(nodes))
+
+	wg := sync.WaitGroup{}
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, p types.Provider) {
+			defer wg.Done()
+			start := time.Now()
+			height, err := checkProviderStatus(p)
+			results[i] = checkResult{height: height, latency: time.Since(start), err: err}
+		}(i, node.provider)
+	}
+	wg.Wait()
+
+	maxHeight := int64(0)
+	for _, r := range results {
+		if r.err == nil && r.height > maxHeight {
+			maxHeight = r.height
+		}
+	}
+
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	for i, node := range nodes {
+		r := results[i]
+		node.latency = r.latency
+		node.lastErr = r.err
+		if r.err != nil {
+			node.healthy = false
+			continue
+		}
+		node.height = r.height
+		node.healthy = maxHeight-r.height <= mp.maxHeightLag
+		if !node.healthy {
+			node.lastErr = fmt.Errorf("behind the latest height: %v < %v", r.height, maxHeight)
+		}
+	}
+}
+
+func checkProviderStatus(p types.Provider) (int64, error) {
+	req, err := types.NewRequest(0, "status")
+	if err != nil {
+		return 0, err
+	}
+	resp, err := p.Call(req)
+	if err != nil {
+		return 0, err
+	} else if resp.Error != nil {
+		return 0, errors.New("provider error: " + string(resp.Error))
+	}
+
+	status := &coretypes.ResultStatus{}
+	if err := tmjson.Unmarshal(resp.Result, status); err != nil {
+		return 0, err
+	}
+	if status.SyncInfo.CatchingUp {
+		return 0, errors.New("node is catching up")
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// Start runs CheckHealth immediately and then periodically until Stop is called.
+// It is required for evicted providers to be used again.
+func (mp *MultiProvider) Start() {
+	mp.mtx.Lock()
+	if mp.done != nil {
+		mp.mtx.Unlock()
+		return
+	}
+	done := make(chan struct{})
+	mp.done = done
+	interval := mp.interval
+	mp.mtx.Unlock()
+
+	mp.CheckHealth()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				mp.CheckHealth()
+			}
+		}
+	}()
+}
+
+func (mp *MultiProvider) Stop() {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	if mp.done == nil {
+		return
+	}
+	close(mp.done)
+	mp.done = nil
+}
+
+type ProviderHealth struct {
+	Provider types.Provider
+	Healthy  bool
+	Height   int64
+	Latency  time.Duration
+	LastErr  error
+}
+
+func (mp *MultiProvider) Health() []ProviderHealth {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	ret := make([]ProviderHealth, len(mp.nodes))
+	for i, node := range mp.nodes {
+		ret[i] = ProviderHealth{
+			Provider: node.provider,
+			Healthy:  node.healthy,
+			Height:   node.height,
+			Latency:  node.latency,
+			LastErr:  node.lastErr,
+		}
+	}
+	return ret
+}
@@ -0,0 +1,218 @@
+package web3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	ctrlertypes "github.com/beatoz/beatoz-go/ctrlers/types"
+	btztypes "github.com/beatoz/beatoz-go/types"
+	btzbytes "github.com/beatoz/beatoz-go/types/bytes"
+	"github.com/beatoz/beatoz-sdk-go/types"
+	"github.com/holiman/uint256"
+)
+
+type DelegationSummary struct {
+	Delegatee btztypes.Address `json:"delegatee"`
+	// Power is the power delegated to Delegatee by the portfolio owner.
+	Power  int64                   `json:"power"`
+	Stakes []*types.RespQueryStake `json:"stakes"`
+	// DelegateeTotalPower is the total power of Delegatee including its self power.
+	DelegateeTotalPower int64 `json:"delegateeTotalPower"`
+	// ShareOfDelegatee is Power / DelegateeTotalPower.
+	ShareOfDelegatee float64 `json:"shareOfDelegatee"`
+}
+
+type PendingUnstake struct {
+	TxHash      btzbytes.HexBytes `json:"txHash"`
+	StakeTxHash btzbytes.HexBytes `json:"stakeTxHash"`
+	Delegatee   btztypes.Address  `json:"delegatee"`
+	Power       int64             `json:"power"`
+	Height      int64             `json:"height"`
+	// RefundHeight is estimated with the current LazyUnbondingBlocks.
+	RefundHeight int64 `json:"refundHeight"`
+}
+
+type StakingPortfolio struct {
+	Address btztypes.Address `json:"address"`
+	Height  int64            `json:"height"`
+	// TotalPower is the sum of all the stakes of Address.
+	TotalPower        int64                  `json:"totalPower"`
+	NetworkTotalPower int64                  `json:"networkTotalPower"`
+	ShareOfTotalPower float64                `json:"shareOfTotalPower"`
+	Delegations       []*DelegationSummary   `json:"delegations"`
+	PendingUnstakes   []*PendingUnstake      `json:"pendingUnstakes"`
+	Reward            *types.RespQueryReward `json:"reward"`
+}
+
+// QueryPortfolio aggregates the stakes, rewards and pending unstakes of addr at height.
+// If height is 0, the latest height is used for all the queries.
+// Pending unstakes are found by tx_search, so they are empty if the node doesn't index txs.
+func (bzweb3 *BeatozWeb3) QueryPortfolio(addr btztypes.Address, height int64) (*StakingPortfolio, error) {
+	if height <= 0 {
+		status, err := bzweb3.Status()
+		if err != nil {
+			return nil, err
+		}
+		height = status.SyncInfo.LatestBlockHeight
+	}
+
+	stakes, err := bzweb3.QueryStakesAt(addr, height)
+	if err != nil {
+		return nil, err
+	}
+	networkPower, err := bzweb3.QueryTotalPower(height)
+	if err != nil {
+		return nil, err
+	}
+	rwd, err := bzweb3.QueryReward(addr, height)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &StakingPortfolio{
+		Address:           addr,
+		Height:            height,
+		NetworkTotalPower: networkPower,
+		Reward:            rwd,
+	}
+
+	byDelegatee := make(map[string]*DelegationSummary)
+	for _, s := range stakes {
+		ret.TotalPower += s.Power
+
+		key := s.To.String()
+		summary, ok := byDelegatee[key]
+		if !ok {
+			summary = &DelegationSummary{Delegatee: s.To}
+			byDelegatee[key] = summary
+			ret.Delegations = append(ret.Delegations, summary)
+		}
+		summary.Power += s.Power
+		summary.Stakes = append(summary.Stakes, s)
+	}
+
+	for _, summary := range ret.Delegations {
+		dgtee, err := bzweb3.QueryDelegateeAt(summary.Delegatee, height)
+		if err != nil {
+			return nil, err
+		}
+		summary.DelegateeTotalPower = dgtee.TotalPower
+		if dgtee.TotalPower > 0 {
+			summary.ShareOfDelegatee = float64(summary.Power) / float64(dgtee.TotalPower)
+		}
+	}
+	sort.SliceStable(ret.Delegations, func(i, j int) bool {
+		return ret.Delegations[i].Power > ret.Delegations[j].Power
+	})
+
+	if networkPower > 0 {
+		ret.ShareOfTotalPower = float64(ret.TotalPower) / float64(networkPower)
+	}
+
+	ret.PendingUnstakes, err = bzweb3.QueryPendingUnstakes(addr, height)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// QueryPendingUnstakes returns the unstaking txs of addr whose power is not refunded yet at height.
+// If height is 0, the latest height is used.
+func (bzweb3 *BeatozWeb3) QueryPendingUnstakes(addr btztypes.Address, height int64) ([]*PendingUnstake, error) {
+	if height <= 0 {
+		status, err := bzweb3.Status()
+		if err != nil {
+			return nil, err
+		}
+		height = status.SyncInfo.LatestBlockHeight
+	}
+
+	govParams, err := bzweb3.QueryGovParamsAt(height)
+	if err != nil {
+		return nil, err
+	}
+	lazyBlocks := govParams.LazyUnbondingBlocks()
+
+	query := types.NewTxQuery().
+		Sender(addr).
+		TrxType(ctrlertypes.TRX_UNSTAKING).
+		HeightRange(height-lazyBlocks+1, height)
+
+	var ret []*PendingUnstake
+	it := bzweb3.IterTxSearch(query, DefaultPerPage, types.OrderAsc)
+	for it.Next() {
+		txRet := it.Value()
+		if txRet.TxResult.Code != 0 {
+			continue
+		}
+		payload, ok := txRet.TrxObj.Payload.(*ctrlertypes.TrxPayloadUnstaking)
+		if !ok {
+			continue
+		}
+
+		stakeTx, err := bzweb3.QueryTransaction(payload.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		power, xerr := btztypes.AmountToPower(stakeTx.TrxObj.Amount)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		ret = append(ret, &PendingUnstake{
+			TxHash:       btzbytes.HexBytes(txRet.Hash),
+			StakeTxHash:  payload.TxHash,
+			Delegatee:    txRet.TrxObj.To,
+			Power:        power,
+			Height:       txRet.Height,
+			RefundHeight: txRet.Height + lazyBlocks,
+		})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// SelectStakesToUnstake picks stakes whose powers sum up to at least the power of amount.
+// If delegatee is not nil, only the stakes delegated to it are picked.
+// The most recent stakes are picked first, since older stakes have more bonding weight for rewards.
+// Unstake each of the returned stakes by its TxHash with NewTrxUnstaking.
+func SelectStakesToUnstake(stakes []*types.RespQueryStake, delegatee btztypes.Address, amount *uint256.Int) ([]*types.RespQueryStake, int64, error) {
+	target, xerr := btztypes.AmountToPower(amount)
+	if xerr != nil {
+		return nil, 0, xerr
+	}
+	if new(uint256.Int).Mod(amount, btztypes.AmountPerPower()).Sign() > 0 {
+		target++
+	}
+	if target <= 0 {
+		return nil, 0, errors.New("amount must be at least 1 power")
+	}
+
+	var candidates []*types.RespQueryStake
+	for _, s := range stakes {
+		if delegatee == nil || bytes.Equal(s.To, delegatee) {
+			candidates = append(candidates, s)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].StartHeight > candidates[j].StartHeight
+	})
+
+	var ret []*types.RespQueryStake
+	sum := int64(0)
+	for _, s := range candidates {
+		if sum >= target {
+			break
+		}
+		ret = append(ret, s)
+		sum += s.Power
+	}
+	if sum < target {
+		return nil, sum, fmt.Errorf("not enough stakes: %v < %v", sum, target)
+	}
+	return ret, sum, nil
+}
@@ -0,0 +1,194 @@
+package web3
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/beatoz/beatoz-go/ctrlers/gov/proposal"
+	ctrlertypes "github.com/beatoz/beatoz-go/ctrlers/types"
+	"github.com/beatoz/beatoz-go/libs/jsonx"
+	btztypes "github.com/beatoz/beatoz-go/types"
+	"github.com/holiman/uint256"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// ProposalSpec has the payload of a proposal tx.
+// Use NewGovParamsProposal, NewTextProposal or NewUpgradeProposal to build it with encoded options.
+type ProposalSpec struct {
+	Message        string
+	StartHeight    int64
+	VotingBlocks   int64
+	ApplyingHeight int64
+	OptType        int32
+	Options        [][]byte
+
+	// InclusionMargin is how many blocks later than the next block the tx is allowed to be included,
+	// which Validate adds to the minimum start height. It isn't sent to the node.
+	// It is 0 by default, which matches the node's rule when the tx is included in the next block.
+	InclusionMargin int64
+}
+
+// NewGovParamsProposal builds a proposal changing the governance parameters to diff.
+// Only the non-zero fields of diff are changed when the proposal is applied.
+func NewGovParamsProposal(msg string, start, period, applyingHeight int64, diff *ctrlertypes.GovParams) (*ProposalSpec, error) {
+	if diff == nil {
+		return nil, errors.New("no governance parameters to change")
+	}
+	opt, err := jsonx.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+	if string(opt) == "{}" {
+		return nil, errors.New("no governance parameters to change")
+	}
+	return &ProposalSpec{
+		Message:        msg,
+		StartHeight:    start,
+		VotingBlocks:   period,
+		ApplyingHeight: applyingHeight,
+		OptType:        proposal.PROPOSAL_GOVPARAMS,
+		Options:        [][]byte{opt},
+	}, nil
+}
+
+// NewTextProposal builds an off-chain proposal whose options are choices.
+// If no choice is given, "yes" and "no" are used.
+func NewTextProposal(msg string, start, period, applyingHeight int64, choices ...string) (*ProposalSpec, error) {
+	if len(choices) == 0 {
+		choices = []string{"yes", "no"}
+	}
+	var opts [][]byte
+	for _, c := range choices {
+		if c == "" {
+			return nil, errors.New("empty choice")
+		}
+		opts = append(opts, []byte(c))
+	}
+	return &ProposalSpec{
+		Message:        msg,
+		StartHeight:    start,
+		VotingBlocks:   period,
+		ApplyingHeight: applyingHeight,
+		OptType:        proposal.PROPOSAL_COMMON,
+		Options:        opts,
+	}, nil
+}
+
+// UpgradePlan is the single option of an upgrade proposal.
+type UpgradePlan struct {
+	Name   string `json:"name"`
+	Height int64  `json:"height"`
+	Info   string `json:"info,omitempty"`
+}
+
+// NewUpgradeProposal builds an off-chain proposal for a software upgrade.
+// The chain doesn't execute the upgrade, so the plan is only recorded as the option to vote on.
+func NewUpgradeProposal(msg string, start, period, applyingHeight int64, plan UpgradePlan) (*ProposalSpec, error) {
+	if plan.Name == "" {
+		return nil, errors.New("upgrade plan has no name")
+	}
+	if plan.Height < applyingHeight {
+		return nil, fmt.Errorf("upgrade height(%v) must not be lower than applying height(%v)", plan.Height, applyingHeight)
+	}
+	opt, err := json.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+	return &ProposalSpec{
+		Message:        msg,
+		StartHeight:    start,
+		VotingBlocks:   period,
+		ApplyingHeight: applyingHeight,
+		OptType:        proposal.PROPOSAL_COMMON,
+		Options:        [][]byte{opt},
+	}, nil
+}
+
+// SuggestProposalHeights returns the heights of a proposal
+// that starts voting startDelay blocks after currHeight and votes for the minimum period.
+// It returns an error if startDelay is lower than inclusionMargin+2,
+// since such a start height doesn't pass Validate with the same InclusionMargin.
+func SuggestProposalHeights(currHeight, startDelay, inclusionMargin int64, govParams *ctrlertypes.GovParams) (start, period, applyingHeight int64, err error) {
+	if minDelay := inclusionMargin + 2; startDelay < minDelay {
+		return 0, 0, 0, fmt.Errorf("start delay(%v) must be at least %v with the inclusion margin(%v)", startDelay, minDelay, inclusionMargin)
+	}
+	start = currHeight + startDelay
+	period = govParams.MinVotingPeriodBlocks()
+	applyingHeight = start + period + govParams.LazyApplyingBlocks()
+	return
+}
+
+// Validate checks the spec against the latest height currHeight before it is sent.
+// The node requires the start height to be higher than the height of the block including the tx,
+// which is currHeight+1 at the earliest, so Validate requires it to be higher than currHeight+1+spec.InclusionMargin.
+// The other checks are the same as the node's.
+func (spec *ProposalSpec) Validate(currHeight int64, govParams *ctrlertypes.GovParams) error {
+	if minStart := currHeight + 1 + spec.InclusionMargin; spec.StartHeight <= minStart {
+		return fmt.Errorf("start height(%v) must be higher than %v, the next height(%v) plus the inclusion margin(%v)",
+			spec.StartHeight, minStart, currHeight+1, spec.InclusionMargin)
+	}
+	if spec.VotingBlocks < govParams.MinVotingPeriodBlocks() || spec.VotingBlocks > govParams.MaxVotingPeriodBlocks() {
+		return fmt.Errorf("voting period(%v) must be in [%v, %v]",
+			spec.VotingBlocks, govParams.MinVotingPeriodBlocks(), govParams.MaxVotingPeriodBlocks())
+	}
+
+	endVotingHeight := spec.StartHeight + spec.VotingBlocks
+	if endVotingHeight < spec.StartHeight {
+		return fmt.Errorf("overflow occurs: start height:%v, voting period:%v", spec.StartHeight, spec.VotingBlocks)
+	}
+	minApplyingHeight := endVotingHeight + govParams.LazyApplyingBlocks()
+	if spec.ApplyingHeight < minApplyingHeight {
+		return fmt.Errorf("applying height(%v) must be equal to or higher than %v", spec.ApplyingHeight, minApplyingHeight)
+	}
+
+	if len(spec.Options) == 0 {
+		return errors.New("proposal must have at least one option")
+	}
+	if spec.OptType == proposal.PROPOSAL_GOVPARAMS {
+		for _, opt := range spec.Options {
+			if err := jsonx.Unmarshal(opt, &ctrlertypes.GovParams{}); err != nil {
+				return fmt.Errorf("wrong governance parameters option: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (spec *ProposalSpec) NewTrx(from btztypes.Address, nonce, gas int64, gasPrice *uint256.Int) *ctrlertypes.Trx {
+	return NewTrxProposal(
+		from, btztypes.ZeroAddress(),
+		nonce, gas, gasPrice,
+		spec.Message, spec.StartHeight, spec.VotingBlocks, spec.ApplyingHeight,
+		spec.OptType, spec.Options...,
+	)
+}
+
+// ValidateProposal validates spec against the latest height and governance parameters.
+func (bzweb3 *BeatozWeb3) ValidateProposal(spec *ProposalSpec) error {
+	status, err := bzweb3.Status()
+	if err != nil {
+		return err
+	}
+	govParams, err := bzweb3.QueryGovParams()
+	if err != nil {
+		return err
+	}
+	return spec.Validate(status.SyncInfo.LatestBlockHeight, govParams)
+}
+
+func (w *Wallet) ProposeSync(spec *ProposalSpec, gas int64, gasPrice *uint256.Int, bzweb3 *BeatozWeb3) (*coretypes.ResultBroadcastTx, error) {
+	if err := bzweb3.ValidateProposal(spec); err != nil {
+		return nil, err
+	}
+	tx := spec.NewTrx(w.Address(), w.GetNonce(), gas, gasPrice)
+	return w.SendTxSync(tx, bzweb3)
+}
+
+func (w *Wallet) ProposeCommit(spec *ProposalSpec, gas int64, gasPrice *uint256.Int, bzweb3 *BeatozWeb3) (*coretypes.ResultBroadcastTxCommit, error) {
+	if err := bzweb3.ValidateProposal(spec); err != nil {
+		return nil, err
+	}
+	tx := spec.NewTrx(w.Address(), w.GetNonce(), gas, gasPrice)
+	return w.SendTxCommit(tx, bzweb3)
+}
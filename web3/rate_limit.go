@@ -0,0 +1,124 @@
+package web3
+
+import (
+	"sync"
+	"time"
+
+	"github.com/beatoz/beatoz-sdk-go/types"
+)
+
+type RateLimit struct {
+	// Rate is the number of calls allowed per second. If it is 0 or less, there is no limit.
+	Rate float64
+	// Burst is the maximum number of calls allowed at once. It is at least 1.
+	Burst int
+}
+
+type RateLimitPolicy struct {
+	// Limit applies to all calls.
+	Limit RateLimit
+	// MaxInFlight is the maximum number of concurrent calls. If it is 0 or less, there is no limit.
+	MaxInFlight int
+	// MethodLimits applies to calls of each method in addition to Limit.
+	MethodLimits map[string]RateLimit
+}
+
+// RateLimitMiddleware blocks calls until they are allowed by the policy.
+// Every provider wrapped by the returned middleware gets its own buckets.
+func RateLimitMiddleware(policy RateLimitPolicy) types.Middleware {
+	return func(next types.Provider) types.Provider {
+		limiter := newRateLimiter(policy)
+		return types.ProviderFunc(func(req *types.JSONRpcReq) (*types.JSONRpcResp, error) {
+			release := limiter.acquire(req.Method)
+			defer release()
+
+			return next.Call(req)
+		})
+	}
+}
+
+type rateLimiter struct {
+	all      *tokenBucket
+	methods  map[string]*tokenBucket
+	inFlight chan struct{}
+}
+
+func newRateLimiter(policy RateLimitPolicy) *rateLimiter {
+	ret := &rateLimiter{
+		all:     newTokenBucket(policy.Limit),
+		methods: make(map[string]*tokenBucket),
+	}
+	for method, limit := range policy.MethodLimits {
+		ret.methods[method] = newTokenBucket(limit)
+	}
+	if policy.MaxInFlight > 0 {
+		ret.inFlight = make(chan struct{}, policy.MaxInFlight)
+	}
+	return ret
+}
+
+func (rl *rateLimiter) acquire(method string) func() {
+	if tb, ok := rl.methods[method]; ok {
+		tb.wait()
+	}
+	rl.all.wait()
+
+	if rl.inFlight == nil {
+		return func() {}
+	}
+	rl.inFlight <- struct{}{}
+	return func() {
+		<-rl.inFlight
+	}
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	mtx sync.Mutex
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+func (tb *tokenBucket) wait() {
+	if d := tb.reserve(); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait until the token is available.
+func (tb *tokenBucket) reserve() time.Duration {
+	if tb.rate <= 0 {
+		return 0
+	}
+
+	tb.mtx.Lock()
+	defer tb.mtx.Unlock()
+
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+
+	tb.tokens--
+	if tb.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+}
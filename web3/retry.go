@@ -0,0 +1,138 @@
+package web3
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/beatoz/beatoz-sdk-go/types"
+)
+
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// Jitter is the fraction (0 ~ 1) of each backoff delay that is randomized.
+	Jitter float64
+	// Retryable reports whether calls of the method may be retried.
+	// If it is nil, IsIdempotentMethod is used.
+	Retryable func(method string) bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  200 * time.Millisecond,
+		MaxDelay:   5 * time.Second,
+		Jitter:     0.5,
+		Retryable:  IsIdempotentMethod,
+	}
+}
+
+// ReadMethods lists the methods which don't change the state of the node, so they can be sent more than once safely.
+// The methods not listed, e.g. "broadcast_tx_*" and "check_tx", which runs the tx against the check state
+// of the node, are never retried.
+var ReadMethods = map[string]bool{
+	"abci_info":            true,
+	"abci_query":           true,
+	"block":                true,
+	"block_by_hash":        true,
+	"block_results":        true,
+	"block_search":         true,
+	"blockchain":           true,
+	"commit":               true,
+	"consensus_params":     true,
+	"consensus_state":      true,
+	"dump_consensus_state": true,
+	"genesis":              true,
+	"header":               true,
+	"header_by_hash":       true,
+	"health":               true,
+	"net_info":             true,
+	"num_unconfirmed_txs":  true,
+	"status":               true,
+	"tx":                   true,
+	"tx_search":            true,
+	"unconfirmed_txs":      true,
+	"validators":           true,
+
+	"account":             true,
+	"delegatee":           true,
+	"gov_params":          true,
+	"proposal":            true,
+	"reward":              true,
+	"stakes":              true,
+	"stakes/total_power":  true,
+	"stakes/voting_power": true,
+	"vm_call":             true,
+	"vm_estimate_gas":     true,
+}
+
+// IsIdempotentMethod reports whether method can be sent more than once safely,
+// which is true only for the methods in ReadMethods.
+func IsIdempotentMethod(method string) bool {
+	return ReadMethods[method]
+}
+
+// RetryMiddleware retries idempotent calls that fail with a transport error
+// or with a 429, 502, 503 or 504 HTTP status, using exponential backoff with jitter.
+// A "Retry-After" delay sent by the server is respected.
+func RetryMiddleware(policy RetryPolicy) types.Middleware {
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsIdempotentMethod
+	}
+
+	return func(next types.Provider) types.Provider {
+		return types.ProviderFunc(func(req *types.JSONRpcReq) (*types.JSONRpcResp, error) {
+			resp, err := next.Call(req)
+			if err == nil || !retryable(req.Method) {
+				return resp, err
+			}
+
+			for attempt := 0; attempt < policy.MaxRetries; attempt++ {
+				delay, ok := policy.retryDelay(attempt, err)
+				if !ok {
+					break
+				}
+				time.Sleep(delay)
+
+				resp, err = next.Call(req)
+				if err == nil {
+					break
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+func (policy *RetryPolicy) retryDelay(attempt int, err error) (time.Duration, bool) {
+	var retryAfter time.Duration
+
+	statusErr := &HttpStatusError{}
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			retryAfter = statusErr.RetryAfter
+		case http.StatusBadGateway, http.StatusGatewayTimeout:
+		default:
+			return 0, false
+		}
+	}
+
+	delay := policy.BaseDelay << uint(attempt)
+	if delay <= 0 || (policy.MaxDelay > 0 && delay > policy.MaxDelay) {
+		delay = policy.MaxDelay
+	}
+	if policy.Jitter > 0 && delay > 0 {
+		jitter := time.Duration(float64(delay) * policy.Jitter)
+		delay = delay - jitter + time.Duration(rand.Int63n(int64(jitter)+1))
+	}
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay, true
+}
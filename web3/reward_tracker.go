@@ -0,0 +1,197 @@
+package web3
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	ctrlertypes "github.com/beatoz/beatoz-go/ctrlers/types"
+	btztypes "github.com/beatoz/beatoz-go/types"
+	"github.com/beatoz/beatoz-sdk-go/types"
+	"github.com/holiman/uint256"
+)
+
+// RewardSample is the reward state of an address at Height.
+// Issued, Withdrawn and Slashed are the amounts changed at LastChangeHeight,
+// and Cumulated is the reward not withdrawn yet.
+type RewardSample struct {
+	Height           int64        `json:"height"`
+	LastChangeHeight int64        `json:"lastChangeHeight"`
+	Issued           *uint256.Int `json:"issued"`
+	Withdrawn        *uint256.Int `json:"withdrawn"`
+	Slashed          *uint256.Int `json:"slashed"`
+	Cumulated        *uint256.Int `json:"cumulated"`
+}
+
+func NewRewardSample(height int64, rwd *types.RespQueryReward) *RewardSample {
+	return &RewardSample{
+		Height:           height,
+		LastChangeHeight: rwd.Height,
+		Issued:           rwd.Issued.Int(),
+		Withdrawn:        rwd.Withdrawn.Int(),
+		Slashed:          rwd.Slashed.Int(),
+		Cumulated:        rwd.Cumulated.Int(),
+	}
+}
+
+// RewardPeriod is the reward earned in the block window (FromHeight, ToHeight].
+type RewardPeriod struct {
+	FromHeight     int64        `json:"fromHeight"`
+	ToHeight       int64        `json:"toHeight"`
+	StartCumulated *uint256.Int `json:"startCumulated"`
+	EndCumulated   *uint256.Int `json:"endCumulated"`
+	// Withdrawn is the sum of the rewards withdrawn by the withdraw txs in the window.
+	Withdrawn *uint256.Int `json:"withdrawn"`
+	// Earned is EndCumulated + Withdrawn - StartCumulated.
+	// Rewards slashed in the window are not counted, and it is 0 if they exceed the issued rewards.
+	Earned *uint256.Int `json:"earned"`
+}
+
+type RewardHistory struct {
+	Address        btztypes.Address `json:"address"`
+	FromHeight     int64            `json:"fromHeight"`
+	ToHeight       int64            `json:"toHeight"`
+	Step           int64            `json:"step"`
+	Samples        []*RewardSample  `json:"samples"`
+	Periods        []*RewardPeriod  `json:"periods"`
+	TotalEarned    *uint256.Int     `json:"totalEarned"`
+	TotalWithdrawn *uint256.Int     `json:"totalWithdrawn"`
+}
+
+// TrackRewards samples the reward of addr every step blocks from fromHeight to toHeight
+// and computes the reward earned in each window between the samples.
+// The withdrawals in the windows are found by tx_search, so the node must index txs.
+func (bzweb3 *BeatozWeb3) TrackRewards(addr btztypes.Address, fromHeight, toHeight, step int64) (*RewardHistory, error) {
+	if fromHeight <= 0 || toHeight < fromHeight {
+		return nil, fmt.Errorf("wrong height range [%v, %v]", fromHeight, toHeight)
+	}
+	if step <= 0 {
+		return nil, errors.New("step must be positive")
+	}
+
+	ret := &RewardHistory{
+		Address:        addr,
+		FromHeight:     fromHeight,
+		ToHeight:       toHeight,
+		Step:           step,
+		TotalEarned:    uint256.NewInt(0),
+		TotalWithdrawn: uint256.NewInt(0),
+	}
+
+	for h := fromHeight; ; h += step {
+		if h > toHeight {
+			h = toHeight
+		}
+		rwd, err := bzweb3.QueryReward(addr, h)
+		if err != nil {
+			return nil, err
+		}
+		ret.Samples = append(ret.Samples, NewRewardSample(h, rwd))
+		if h == toHeight {
+			break
+		}
+	}
+
+	withdrawals, err := bzweb3.queryWithdrawals(addr, fromHeight+1, toHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(ret.Samples); i++ {
+		start, end := ret.Samples[i-1], ret.Samples[i]
+		period := &RewardPeriod{
+			FromHeight:     start.Height,
+			ToHeight:       end.Height,
+			StartCumulated: start.Cumulated,
+			EndCumulated:   end.Cumulated,
+			Withdrawn:      uint256.NewInt(0),
+			Earned:         uint256.NewInt(0),
+		}
+		for _, w := range withdrawals {
+			if w.height > start.Height && w.height <= end.Height {
+				_ = period.Withdrawn.Add(period.Withdrawn, w.amount)
+			}
+		}
+
+		total := new(uint256.Int).Add(end.Cumulated, period.Withdrawn)
+		if total.Cmp(start.Cumulated) > 0 {
+			_ = period.Earned.Sub(total, start.Cumulated)
+		}
+
+		_ = ret.TotalEarned.Add(ret.TotalEarned, period.Earned)
+		_ = ret.TotalWithdrawn.Add(ret.TotalWithdrawn, period.Withdrawn)
+		ret.Periods = append(ret.Periods, period)
+	}
+	return ret, nil
+}
+
+type rewardWithdrawal struct {
+	height int64
+	amount *uint256.Int
+}
+
+func (bzweb3 *BeatozWeb3) queryWithdrawals(addr btztypes.Address, fromHeight, toHeight int64) ([]*rewardWithdrawal, error) {
+	if fromHeight > toHeight {
+		return nil, nil
+	}
+	query := types.NewTxQuery().
+		Sender(addr).
+		TrxType(ctrlertypes.TRX_WITHDRAW).
+		HeightRange(fromHeight, toHeight)
+
+	var ret []*rewardWithdrawal
+	it := bzweb3.IterTxSearch(query, DefaultPerPage, types.OrderAsc)
+	for it.Next() {
+		txRet := it.Value()
+		if txRet.TxResult.Code != 0 {
+			continue
+		}
+		payload, ok := txRet.TrxObj.Payload.(*ctrlertypes.TrxPayloadWithdraw)
+		if !ok || payload.ReqAmt == nil {
+			continue
+		}
+		ret = append(ret, &rewardWithdrawal{
+			height: txRet.Height,
+			amount: payload.ReqAmt,
+		})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (hist *RewardHistory) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(hist)
+}
+
+// WriteCSV writes a row per period with the amounts in the smallest unit.
+func (hist *RewardHistory) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"address", "from_height", "to_height",
+		"start_cumulated", "end_cumulated", "withdrawn", "earned",
+	}); err != nil {
+		return err
+	}
+	for _, p := range hist.Periods {
+		if err := cw.Write([]string{
+			hist.Address.String(),
+			strconv.FormatInt(p.FromHeight, 10),
+			strconv.FormatInt(p.ToHeight, 10),
+			p.StartCumulated.Dec(),
+			p.EndCumulated.Dec(),
+			p.Withdrawn.Dec(),
+			p.Earned.Dec(),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
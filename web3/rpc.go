@@ -8,7 +8,6 @@ import (
 	btztypes "github.com/beatoz/beatoz-go/types"
 	btzbytes "github.com/beatoz/beatoz-go/types/bytes"
 	"github.com/beatoz/beatoz-sdk-go/types"
-	"github.com/holiman/uint256"
 	tmjson "github.com/tendermint/tendermint/libs/json"
 	coretypes "github.com/tendermint/tendermint/rpc/core/types"
 	"strconv"
@@ -53,9 +52,15 @@ func (bzweb3 *BeatozWeb3) GetGovParams() (*ctrlertypes.GovParams, error) {
 }
 
 func (bzweb3 *BeatozWeb3) QueryGovParams() (*ctrlertypes.GovParams, error) {
+	return bzweb3.QueryGovParamsAt(0)
+}
+
+// QueryGovParamsAt returns the governance parameters at height.
+// If height is 0, the latest state is queried.
+func (bzweb3 *BeatozWeb3) QueryGovParamsAt(height int64) (*ctrlertypes.GovParams, error) {
 	queryResp := &rpc.QueryResult{}
 
-	if req, err := bzweb3.NewRequest("gov_params", strconv.FormatInt(0, 10)); err != nil {
+	if req, err := bzweb3.NewRequest("gov_params", strconv.FormatInt(height, 10)); err != nil {
 		panic(err)
 	} else if resp, err := bzweb3.provider.Call(req); err != nil {
 		return nil, err
@@ -79,9 +84,15 @@ func (bzweb3 *BeatozWeb3) GetAccount(addr btztypes.Address) (*ctrlertypes.Accoun
 }
 
 func (bzweb3 *BeatozWeb3) QueryAccount(addr btztypes.Address) (*ctrlertypes.Account, error) {
+	return bzweb3.QueryAccountAt(addr, 0)
+}
+
+// QueryAccountAt returns the account of addr at height.
+// If height is 0, the latest state is queried.
+func (bzweb3 *BeatozWeb3) QueryAccountAt(addr btztypes.Address, height int64) (*ctrlertypes.Account, error) {
 	queryResp := &rpc.QueryResult{}
 
-	if req, err := bzweb3.NewRequest("account", addr.String(), strconv.FormatInt(0, 10)); err != nil {
+	if req, err := bzweb3.NewRequest("account", addr.String(), strconv.FormatInt(height, 10)); err != nil {
 		panic(err)
 	} else if resp, err := bzweb3.provider.Call(req); err != nil {
 		return nil, err
@@ -95,7 +106,7 @@ func (bzweb3 *BeatozWeb3) QueryAccount(addr btztypes.Address) (*ctrlertypes.Acco
 		Address btztypes.Address  `json:"address"`
 		Name    string            `json:"name,omitempty"`
 		Nonce   int64             `json:"nonce,string"`
-		Balance string            `json:"balance"`
+		Balance types.Amount      `json:"balance"`
 		Code    btzbytes.HexBytes `json:"code,omitempty"`
 		DocURL  string            `json:"docURL,omitempty"`
 	}{}
@@ -103,18 +114,11 @@ func (bzweb3 *BeatozWeb3) QueryAccount(addr btztypes.Address) (*ctrlertypes.Acco
 	if err := tmjson.Unmarshal(queryResp.Value, _acct); err != nil {
 		return nil, err
 	} else {
-		var bal *uint256.Int
-		if strings.HasPrefix(_acct.Balance, "0x") {
-			bal = uint256.MustFromHex(_acct.Balance)
-		} else {
-			bal = uint256.MustFromDecimal(_acct.Balance)
-		}
-
 		return &ctrlertypes.Account{
 			Address: _acct.Address,
 			Name:    _acct.Name,
 			Nonce:   _acct.Nonce,
-			Balance: bal,
+			Balance: _acct.Balance.Int(),
 			Code:    _acct.Code,
 			DocURL:  _acct.DocURL,
 		}, nil
@@ -122,10 +126,16 @@ func (bzweb3 *BeatozWeb3) QueryAccount(addr btztypes.Address) (*ctrlertypes.Acco
 }
 
 func (bzweb3 *BeatozWeb3) QueryDelegatee(addr btztypes.Address) (*types.RespQueryDelegatee, error) {
+	return bzweb3.QueryDelegateeAt(addr, 0)
+}
+
+// QueryDelegateeAt returns the delegatee of addr at height.
+// If height is 0, the latest state is queried.
+func (bzweb3 *BeatozWeb3) QueryDelegateeAt(addr btztypes.Address, height int64) (*types.RespQueryDelegatee, error) {
 	queryResp := &rpc.QueryResult{}
 	dgtee := &types.RespQueryDelegatee{}
 
-	if req, err := bzweb3.NewRequest("delegatee", addr.String(), strconv.FormatInt(0, 10)); err != nil {
+	if req, err := bzweb3.NewRequest("delegatee", addr.String(), strconv.FormatInt(height, 10)); err != nil {
 		panic(err)
 	} else if resp, err := bzweb3.provider.Call(req); err != nil {
 		return nil, err
@@ -141,9 +151,15 @@ func (bzweb3 *BeatozWeb3) QueryDelegatee(addr btztypes.Address) (*types.RespQuer
 }
 
 func (bzweb3 *BeatozWeb3) QueryStakes(addr btztypes.Address) ([]*types.RespQueryStake, error) {
+	return bzweb3.QueryStakesAt(addr, 0)
+}
+
+// QueryStakesAt returns the stakes delegated by addr at height.
+// If height is 0, the latest state is queried.
+func (bzweb3 *BeatozWeb3) QueryStakesAt(addr btztypes.Address, height int64) ([]*types.RespQueryStake, error) {
 	queryResp := &rpc.QueryResult{}
 	var stakes []*types.RespQueryStake
-	if req, err := bzweb3.NewRequest("stakes", addr.String(), strconv.FormatInt(0, 10)); err != nil {
+	if req, err := bzweb3.NewRequest("stakes", addr.String(), strconv.FormatInt(height, 10)); err != nil {
 		panic(err)
 	} else if resp, err := bzweb3.provider.Call(req); err != nil {
 		return nil, err
@@ -0,0 +1,123 @@
+package web3
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/beatoz/beatoz-sdk-go/types"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// heightParam returns the height param for tendermint's endpoints,
+// which take null (not 0) as the latest height.
+func heightParam(height int64) interface{} {
+	if height <= 0 {
+		return nil
+	}
+	return strconv.FormatInt(height, 10)
+}
+
+// QueryBlock returns the block at height with its transactions decoded.
+// If height is 0, the latest block is returned.
+func (bzweb3 *BeatozWeb3) QueryBlock(height int64) (*types.BlockResult, error) {
+	retBlock := &coretypes.ResultBlock{}
+
+	if req, err := bzweb3.NewRequest("block", heightParam(height)); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, retBlock); err != nil {
+		return nil, err
+	}
+
+	return types.NewBlockResult(retBlock)
+}
+
+func (bzweb3 *BeatozWeb3) QueryBlockByHash(hash []byte) (*types.BlockResult, error) {
+	retBlock := &coretypes.ResultBlock{}
+
+	if req, err := bzweb3.NewRequest("block_by_hash", hash); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, retBlock); err != nil {
+		return nil, err
+	} else if retBlock.Block == nil {
+		return nil, errors.New("block not found")
+	}
+
+	return types.NewBlockResult(retBlock)
+}
+
+// QueryBlockResults returns the results of the transactions and the begin/end block events at height.
+// If height is 0, the latest results are returned.
+func (bzweb3 *BeatozWeb3) QueryBlockResults(height int64) (*coretypes.ResultBlockResults, error) {
+	ret := &coretypes.ResultBlockResults{}
+
+	if req, err := bzweb3.NewRequest("block_results", heightParam(height)); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// QueryBlockchain returns the block metas between minHeight and maxHeight in descending order.
+// The node returns at most 20 metas per call.
+func (bzweb3 *BeatozWeb3) QueryBlockchain(minHeight, maxHeight int64) (*coretypes.ResultBlockchainInfo, error) {
+	ret := &coretypes.ResultBlockchainInfo{}
+
+	if req, err := bzweb3.NewRequest("blockchain", heightParam(minHeight), heightParam(maxHeight)); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// QueryCommit returns the signed header and the commit at height.
+// If height is 0, the commit of the latest block is returned and it may not be canonical yet.
+func (bzweb3 *BeatozWeb3) QueryCommit(height int64) (*coretypes.ResultCommit, error) {
+	ret := &coretypes.ResultCommit{}
+
+	if req, err := bzweb3.NewRequest("commit", heightParam(height)); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// QueryHeader returns the block header at height.
+// It is read from the commit, since the node has no "header" endpoint.
+func (bzweb3 *BeatozWeb3) QueryHeader(height int64) (*tmtypes.Header, error) {
+	commit, err := bzweb3.QueryCommit(height)
+	if err != nil {
+		return nil, err
+	}
+	if commit.SignedHeader.Header == nil {
+		return nil, errors.New("no header")
+	}
+	return commit.SignedHeader.Header, nil
+}
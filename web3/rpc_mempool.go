@@ -0,0 +1,87 @@
+package web3
+
+import (
+	"errors"
+	"strconv"
+
+	ctrlertypes "github.com/beatoz/beatoz-go/ctrlers/types"
+	btztypes "github.com/beatoz/beatoz-go/types"
+	"github.com/beatoz/beatoz-sdk-go/types"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// QueryUnconfirmedTxs returns up to limit pending txs in the mempool with their transactions decoded.
+// If limit is 0, the node's default limit (30) is used. The node allows at most 100.
+func (bzweb3 *BeatozWeb3) QueryUnconfirmedTxs(limit int) (*types.UnconfirmedTrxsResult, error) {
+	retTxs := &coretypes.ResultUnconfirmedTxs{}
+
+	var _limit interface{}
+	if limit > 0 {
+		_limit = strconv.Itoa(limit)
+	}
+
+	if req, err := bzweb3.NewRequest("unconfirmed_txs", _limit); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, retTxs); err != nil {
+		return nil, err
+	}
+
+	txs, err := types.NewPendingTrxs(retTxs.Txs)
+	if err != nil {
+		return nil, err
+	}
+	return &types.UnconfirmedTrxsResult{
+		Count:      retTxs.Count,
+		Total:      retTxs.Total,
+		TotalBytes: retTxs.TotalBytes,
+		Txs:        txs,
+	}, nil
+}
+
+// QueryPendingTxsOf returns the pending txs sent from addr among the first limit txs in the mempool.
+func (bzweb3 *BeatozWeb3) QueryPendingTxsOf(addr btztypes.Address, limit int) ([]*types.PendingTrx, error) {
+	ret, err := bzweb3.QueryUnconfirmedTxs(limit)
+	if err != nil {
+		return nil, err
+	}
+	return ret.FilterBySender(addr), nil
+}
+
+// QueryNumUnconfirmedTxs returns the number and the size of the pending txs. Its Txs is always empty.
+func (bzweb3 *BeatozWeb3) QueryNumUnconfirmedTxs() (*coretypes.ResultUnconfirmedTxs, error) {
+	ret := &coretypes.ResultUnconfirmedTxs{}
+
+	if req, err := bzweb3.NewRequest("num_unconfirmed_txs"); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// CheckTx runs CheckTx of the signed tx without adding it to the mempool.
+// It is not a dry run: the node executes the tx against its check state,
+// so the nonce and the balance of the sender advance on that node until the next block is committed.
+// Broadcasting the same tx to that node before then fails with an invalid nonce.
+// Use VmEstimateGas to try a contract call without the side effect.
+func (bzweb3 *BeatozWeb3) CheckTx(tx *ctrlertypes.Trx) (*coretypes.ResultCheckTx, error) {
+	resp, err := bzweb3.sendTransaction(tx, "check_tx")
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &coretypes.ResultCheckTx{}
+	if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,99 @@
+package web3
+
+import (
+	"errors"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// Health returns nil if the node is alive.
+func (bzweb3 *BeatozWeb3) Health() error {
+	if req, err := bzweb3.NewRequest("health"); err != nil {
+		return err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return err
+	} else if resp.Error != nil {
+		return errors.New("provider error: " + string(resp.Error))
+	}
+	return nil
+}
+
+func (bzweb3 *BeatozWeb3) NetInfo() (*coretypes.ResultNetInfo, error) {
+	ret := &coretypes.ResultNetInfo{}
+
+	if req, err := bzweb3.NewRequest("net_info"); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// QueryConsensusParams returns the consensus params at height.
+// If height is 0, the latest params are returned.
+func (bzweb3 *BeatozWeb3) QueryConsensusParams(height int64) (*coretypes.ResultConsensusParams, error) {
+	ret := &coretypes.ResultConsensusParams{}
+
+	if req, err := bzweb3.NewRequest("consensus_params", heightParam(height)); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ConsensusState returns the simplified round state of the node.
+func (bzweb3 *BeatozWeb3) ConsensusState() (*coretypes.ResultConsensusState, error) {
+	ret := &coretypes.ResultConsensusState{}
+
+	if req, err := bzweb3.NewRequest("consensus_state"); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// DumpConsensusState returns the full round state of the node and the round states of its peers.
+func (bzweb3 *BeatozWeb3) DumpConsensusState() (*coretypes.ResultDumpConsensusState, error) {
+	ret := &coretypes.ResultDumpConsensusState{}
+
+	if req, err := bzweb3.NewRequest("dump_consensus_state"); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (bzweb3 *BeatozWeb3) ABCIInfo() (*coretypes.ResultABCIInfo, error) {
+	ret := &coretypes.ResultABCIInfo{}
+
+	if req, err := bzweb3.NewRequest("abci_info"); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
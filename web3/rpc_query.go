@@ -0,0 +1,49 @@
+package web3
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/beatoz/beatoz-go/rpc"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+)
+
+// Query calls the query route with params followed by height, as every beatoz query route does.
+// (e.g. Query("account", 0, addr.String()) calls "account" with [addr, "0"])
+// If height is 0, the latest state is queried.
+// It returns an error if the result code is not 0.
+func (bzweb3 *BeatozWeb3) Query(route string, height int64, params ...interface{}) (*rpc.QueryResult, error) {
+	queryResp := &rpc.QueryResult{}
+
+	args := append(append([]interface{}{}, params...), strconv.FormatInt(height, 10))
+	if req, err := bzweb3.NewRequest(route, args...); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, queryResp); err != nil {
+		return nil, err
+	} else if queryResp.Code != 0 {
+		return nil, errors.New(queryResp.Log)
+	}
+	return queryResp, nil
+}
+
+// DecodeQueryValue decodes the value of the query result into T.
+func DecodeQueryValue[T any](queryResp *rpc.QueryResult) (*T, error) {
+	ret := new(T)
+	if err := tmjson.Unmarshal(queryResp.Value, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// QueryAs calls bzweb3.Query and decodes the value of its result into T.
+func QueryAs[T any](bzweb3 *BeatozWeb3, route string, height int64, params ...interface{}) (*T, error) {
+	queryResp, err := bzweb3.Query(route, height, params...)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeQueryValue[T](queryResp)
+}
@@ -0,0 +1,54 @@
+package web3
+
+import (
+	"errors"
+	"strconv"
+
+	ctrlertypes "github.com/beatoz/beatoz-go/ctrlers/types"
+	"github.com/beatoz/beatoz-sdk-go/types"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// QueryTxSearch returns a page of the txs matched by query with their transactions decoded.
+// page starts from 1, and orderBy is types.OrderAsc or types.OrderDesc (by height).
+func (bzweb3 *BeatozWeb3) QueryTxSearch(query *types.TxQuery, page, perPage int, orderBy string) (*types.TrxSearchResult, error) {
+	retSearch := &coretypes.ResultTxSearch{}
+
+	if err := query.Err(); err != nil {
+		return nil, err
+	}
+	if page == 0 {
+		page = 1
+	}
+	if orderBy == "" {
+		orderBy = types.OrderAsc
+	}
+	_page := strconv.Itoa(page)
+	_perPage := strconv.Itoa(perPage)
+
+	if req, err := bzweb3.NewRequest("tx_search", query.String(), false, _page, _perPage, orderBy); err != nil {
+		return nil, err
+	} else if resp, err := bzweb3.provider.Call(req); err != nil {
+		return nil, err
+	} else if resp.Error != nil {
+		return nil, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, retSearch); err != nil {
+		return nil, err
+	}
+
+	ret := &types.TrxSearchResult{
+		TotalCount: retSearch.TotalCount,
+	}
+	for _, resultTx := range retSearch.Txs {
+		trx := &ctrlertypes.Trx{}
+		if err := trx.Decode(resultTx.Tx); err != nil {
+			return nil, err
+		}
+		ret.Txs = append(ret.Txs, &types.TrxResult{
+			ResultTx: resultTx,
+			TrxObj:   trx,
+		})
+	}
+	return ret, nil
+}
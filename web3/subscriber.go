@@ -1,32 +1,61 @@
 package web3
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"github.com/beatoz/beatoz-sdk-go/types"
 	"github.com/gorilla/websocket"
 	"github.com/tendermint/tendermint/libs/json"
+	"log/slog"
 	"sync"
 )
 
+// Subscriber has no reconnect path. If the connection drops, the read failure is logged
+// and the caller has to Start a new subscription.
 type Subscriber struct {
-	url   string
-	conn  *websocket.Conn
-	query string
+	url    string
+	conn   *websocket.Conn
+	query  string
+	logger *slog.Logger
+	// errHandler is called with the error responses of the node and the read failure ending the subscription.
+	errHandler func(*Subscriber, error)
 
 	done chan struct{}
 	mtx  sync.Mutex
 }
 
-func NewSubscriber(url string) (*Subscriber, error) {
-	return &Subscriber{
-		url:  url,
-		done: make(chan struct{}),
-	}, nil
+func NewSubscriber(url string, opts ...func(*Subscriber)) (*Subscriber, error) {
+	ret := &Subscriber{
+		url:    url,
+		logger: discardLogger(),
+		done:   make(chan struct{}),
+	}
+	for _, cb := range opts {
+		cb(ret)
+	}
+	return ret, nil
+}
+
+func WithSubscriberLogger(logger *slog.Logger) func(*Subscriber) {
+	return func(sub *Subscriber) {
+		if logger != nil {
+			sub.logger = logger
+		}
+	}
+}
+
+// WithSubscriberErrorHandler sets the callback called with the subscription errors,
+// which are only logged by default.
+func WithSubscriberErrorHandler(handler func(*Subscriber, error)) func(*Subscriber) {
+	return func(sub *Subscriber) {
+		sub.errHandler = handler
+	}
 }
 
 func (sub *Subscriber) Start(query string, callback func(*Subscriber, []byte)) error {
 	conn, _, err := websocket.DefaultDialer.Dial(sub.url, nil)
 	if err != nil {
+		sub.logger.Warn("subscriber dial failed", "url", sub.url, "err", err)
 		return err
 	}
 
@@ -42,18 +71,21 @@ func (sub *Subscriber) Start(query string, callback func(*Subscriber, []byte)) e
 
 	err = conn.WriteMessage(websocket.TextMessage, bz)
 	if err != nil {
+		sub.logger.Warn("subscribe request failed", "url", sub.url, "query", query, "err", err)
 		return err
 	}
 
 	_, _, err = conn.ReadMessage()
 	if err != nil {
+		sub.logger.Warn("subscribe response failed", "url", sub.url, "query", query, "err", err)
 		return err
 	}
 
 	sub.conn = conn
 	sub.query = query
+	sub.logger.Debug("subscribed", "url", sub.url, "query", query)
 
-	go receiveRoutine(sub, callback)
+	go receiveRoutine(sub, conn, callback)
 
 	return nil
 }
@@ -84,53 +116,57 @@ func (sub *Subscriber) Stop() {
 	close(sub.done)
 
 	_ = sub.conn.Close()
+	sub.logger.Debug("unsubscribed", "url", sub.url, "query", sub.query)
 	sub.conn = nil
 	sub.query = ""
 
 }
 
-var wg = sync.WaitGroup{}
-
-func receiveRoutine(sub *Subscriber, callback func(*Subscriber, []byte)) {
+// receiveRoutine reads the events from conn until it is closed.
+// Malformed frames are dropped, and error responses and read failures are passed to the error handler.
+func receiveRoutine(sub *Subscriber, conn *websocket.Conn, callback func(*Subscriber, []byte)) {
+	query := sub.query
 	for {
-		select {
-		case <-sub.done:
-			break
-		default:
-			if sub.conn == nil {
-				break
+		ty, msg, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-sub.done:
+				// connection is closed by Stop
+			default:
+				sub.logger.Error("subscriber read failed", "url", sub.url, "query", query, "err", err)
+				sub.handleError(err)
 			}
+			return
+		}
 
-			ty, msg, err := sub.conn.ReadMessage()
-			if err != nil {
-				_, ok := <-sub.done
-				if !ok {
-					// connection is closed
-				} else {
-					fmt.Println("error", err)
-				}
-				break
-			}
+		if ty != websocket.TextMessage {
+			sub.logger.Debug("dropped event", "reason", "not a text message", "type", ty, "bytes", len(msg))
+			continue
+		}
 
-			if ty == websocket.TextMessage {
-				resp := &types.JSONRpcResp{}
-				if err := json.Unmarshal(msg, resp); err != nil {
-					panic(err)
-				}
-
-				if resp.Error != nil {
-					panic(string(resp.Error))
-					break
-				}
-
-				if len(resp.Result) > 2 && callback != nil {
-					callback(sub, resp.Result)
-				}
-			} else {
-				fmt.Println("ReadMessage", "other type", ty, msg)
-			}
+		resp := &types.JSONRpcResp{}
+		if err := json.Unmarshal(msg, resp); err != nil {
+			sub.logger.Warn("dropped event", "reason", "invalid json-rpc response", "err", err, "bytes", len(msg))
+			continue
+		}
+
+		if resp.Error != nil {
+			sub.logger.Error("subscription error", "url", sub.url, "query", query, "error", string(resp.Error))
+			sub.handleError(errors.New("subscription error: " + string(resp.Error)))
+			continue
+		}
+
+		if len(resp.Result) > 2 && callback != nil {
+			sub.logger.Log(context.Background(), LevelTrace, "event received", "query", query, "bytes", len(resp.Result))
+			callback(sub, resp.Result)
+		} else {
+			sub.logger.Debug("dropped event", "reason", "empty result or no callback", "bytes", len(resp.Result))
 		}
 	}
+}
 
-	wg.Done()
+func (sub *Subscriber) handleError(err error) {
+	if sub.errHandler != nil {
+		sub.errHandler(sub, err)
+	}
 }
@@ -0,0 +1,491 @@
+package web3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beatoz/beatoz-go/rpc"
+	btztypes "github.com/beatoz/beatoz-go/types"
+	"github.com/beatoz/beatoz-go/types/xerrors"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+const DefaultMonitorInterval = 10 * time.Second
+
+type ValidatorEventType string
+
+const (
+	// ValidatorJoined is emitted when an address enters the validator set.
+	ValidatorJoined ValidatorEventType = "joined"
+	// ValidatorLeft is emitted when an address leaves the validator set without being punished,
+	// e.g. when it is outpowered by other delegatees or its operator unbonds all of its power.
+	ValidatorLeft ValidatorEventType = "left"
+	// ValidatorJailed is emitted when a validator leaves the set because it is punished.
+	// The node removes the delegatee of a validator that misses too many blocks
+	// and unbonds all the power delegated to it. Reason tells which punishment it was.
+	ValidatorJailed ValidatorEventType = "jailed"
+	// ValidatorPowerChanged is emitted when the voting power of a validator changes.
+	ValidatorPowerChanged ValidatorEventType = "power_changed"
+	// ValidatorSlashed is emitted for every "vpower.slashing" begin block event of a validator.
+	ValidatorSlashed ValidatorEventType = "slashed"
+	// ValidatorMissedBlocks is emitted when the not-signed block count of a validator increases.
+	ValidatorMissedBlocks ValidatorEventType = "missed_blocks"
+)
+
+type ValidatorEvent struct {
+	Type    ValidatorEventType `json:"type"`
+	Height  int64              `json:"height"`
+	Address btztypes.Address   `json:"address"`
+	// OldPower and NewPower are voting powers. NewPower is 0 if the validator left the set.
+	OldPower int64 `json:"oldPower"`
+	NewPower int64 `json:"newPower"`
+	// SlashedPower and Evidence are set for ValidatorSlashed.
+	SlashedPower int64  `json:"slashedPower,omitempty"`
+	Evidence     string `json:"evidence,omitempty"`
+	// Reason is set for ValidatorJailed, which is ValidatorMissedBlocks or ValidatorSlashed.
+	Reason ValidatorEventType `json:"reason,omitempty"`
+	// OldMissedBlocks and NewMissedBlocks are set for ValidatorMissedBlocks.
+	OldMissedBlocks int64 `json:"oldMissedBlocks,omitempty"`
+	NewMissedBlocks int64 `json:"newMissedBlocks,omitempty"`
+	// MaxMissedBlocks is how many blocks a validator can miss in an inflation cycle before it is jailed.
+	MaxMissedBlocks int64 `json:"maxMissedBlocks,omitempty"`
+}
+
+func (evt *ValidatorEvent) String() string {
+	switch evt.Type {
+	case ValidatorSlashed:
+		return fmt.Sprintf("validator %v %v at %v: %v power slashed for %v",
+			evt.Address, evt.Type, evt.Height, evt.SlashedPower, evt.Evidence)
+	case ValidatorJailed:
+		return fmt.Sprintf("validator %v %v at %v for %v: power %v -> %v",
+			evt.Address, evt.Type, evt.Height, evt.Reason, evt.OldPower, evt.NewPower)
+	case ValidatorMissedBlocks:
+		return fmt.Sprintf("validator %v %v at %v: %v -> %v (max: %v)",
+			evt.Address, evt.Type, evt.Height, evt.OldMissedBlocks, evt.NewMissedBlocks, evt.MaxMissedBlocks)
+	default:
+		return fmt.Sprintf("validator %v %v at %v: power %v -> %v",
+			evt.Address, evt.Type, evt.Height, evt.OldPower, evt.NewPower)
+	}
+}
+
+type ValidatorStat struct {
+	Address             btztypes.Address `json:"address"`
+	Power               int64            `json:"power"`
+	NotSignedBlockCount int64            `json:"notSignedBlockCount"`
+}
+
+type ValidatorSnapshot struct {
+	Height          int64                     `json:"height"`
+	MaxMissedBlocks int64                     `json:"maxMissedBlocks"`
+	Validators      map[string]*ValidatorStat `json:"validators"`
+}
+
+// ValidatorMonitor diffs the validator set and the stats of the validators between heights
+// and calls its handler with the differences as ValidatorEvents.
+type ValidatorMonitor struct {
+	bzweb3   *BeatozWeb3
+	handler  func(*ValidatorEvent)
+	interval time.Duration
+	watched  map[string]bool
+	logger   *slog.Logger
+
+	last *ValidatorSnapshot
+	done chan struct{}
+	mtx  sync.Mutex
+}
+
+func NewValidatorMonitor(bzweb3 *BeatozWeb3, handler func(*ValidatorEvent), opts ...func(*ValidatorMonitor)) *ValidatorMonitor {
+	ret := &ValidatorMonitor{
+		bzweb3:   bzweb3,
+		handler:  handler,
+		interval: DefaultMonitorInterval,
+		logger:   discardLogger(),
+	}
+	for _, cb := range opts {
+		cb(ret)
+	}
+	return ret
+}
+
+func WithMonitorInterval(d time.Duration) func(*ValidatorMonitor) {
+	return func(m *ValidatorMonitor) {
+		if d > 0 {
+			m.interval = d
+		}
+	}
+}
+
+// WithWatchedValidators makes the monitor emit events only for addrs.
+// By default, events of all the validators are emitted.
+func WithWatchedValidators(addrs ...btztypes.Address) func(*ValidatorMonitor) {
+	return func(m *ValidatorMonitor) {
+		m.watched = make(map[string]bool)
+		for _, addr := range addrs {
+			m.watched[addr.String()] = true
+		}
+	}
+}
+
+func WithMonitorLogger(logger *slog.Logger) func(*ValidatorMonitor) {
+	return func(m *ValidatorMonitor) {
+		if logger != nil {
+			m.logger = logger
+		}
+	}
+}
+
+func (m *ValidatorMonitor) isWatched(addr string) bool {
+	return m.watched == nil || m.watched[addr]
+}
+
+// Snapshot returns the validator set and the stats of the watched validators at height.
+// If height is 0, the latest height is used.
+func (m *ValidatorMonitor) Snapshot(height int64) (*ValidatorSnapshot, error) {
+	if height <= 0 {
+		status, err := m.bzweb3.Status()
+		if err != nil {
+			return nil, err
+		}
+		height = status.SyncInfo.LatestBlockHeight
+	}
+
+	govParams, err := m.bzweb3.QueryGovParamsAt(height)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := m.bzweb3.AllValidators(height)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &ValidatorSnapshot{
+		Height:          height,
+		MaxMissedBlocks: govParams.InflationCycleBlocks() - govParams.MinSignedBlocks(),
+		Validators:      make(map[string]*ValidatorStat),
+	}
+	for _, val := range vals {
+		addr := btztypes.Address(val.Address)
+		if !m.isWatched(addr.String()) {
+			continue
+		}
+		dgtee, err := m.bzweb3.QueryDelegateeAt(addr, height)
+		if err != nil {
+			return nil, err
+		}
+		ret.Validators[addr.String()] = &ValidatorStat{
+			Address:             addr,
+			Power:               val.VotingPower,
+			NotSignedBlockCount: dgtee.NotSignedBlockCount,
+		}
+	}
+	return ret, nil
+}
+
+// Check takes the snapshot at height, diffs it against the last one and calls the handler with the events.
+// The slashings are found in the begin block events of the heights after the last snapshot.
+// The first call only records the snapshot and emits no events.
+// The handler is called without holding the lock of the monitor, so it may call Last or Stop.
+func (m *ValidatorMonitor) Check(height int64) ([]*ValidatorEvent, error) {
+	evts, err := m.check(height)
+	if err != nil {
+		return nil, err
+	}
+	for _, evt := range evts {
+		m.logger.Info("validator event", "type", evt.Type, "height", evt.Height, "address", evt.Address)
+		if m.handler != nil {
+			m.handler(evt)
+		}
+	}
+	return evts, nil
+}
+
+func (m *ValidatorMonitor) check(height int64) ([]*ValidatorEvent, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	curr, err := m.Snapshot(height)
+	if err != nil {
+		return nil, err
+	}
+	prev := m.last
+	if prev != nil && curr.Height <= prev.Height {
+		return nil, nil
+	}
+	if prev == nil {
+		m.last = curr
+		return nil, nil
+	}
+
+	evts, err := m.diff(prev, curr)
+	if err != nil {
+		return nil, err
+	}
+	m.last = curr
+	return evts, nil
+}
+
+func (m *ValidatorMonitor) diff(prev, curr *ValidatorSnapshot) ([]*ValidatorEvent, error) {
+	slashings, err := m.slashings(prev.Height+1, curr.Height)
+	if err != nil {
+		return nil, err
+	}
+	evts := slashings
+
+	for key, oldStat := range prev.Validators {
+		if _, ok := curr.Validators[key]; ok {
+			continue
+		}
+		evt := &ValidatorEvent{
+			Type:     ValidatorLeft,
+			Height:   curr.Height,
+			Address:  oldStat.Address,
+			OldPower: oldStat.Power,
+		}
+		for _, slashed := range slashings {
+			if slashed.Address.String() == key {
+				evt.Type, evt.Reason = ValidatorJailed, ValidatorSlashed
+			}
+		}
+		if evt.Type == ValidatorLeft {
+			missed, err := m.removedForMissedBlocks(oldStat.Address, prev.Height+1, curr.Height)
+			if err != nil {
+				return nil, err
+			}
+			if missed {
+				evt.Type, evt.Reason = ValidatorJailed, ValidatorMissedBlocks
+			}
+		}
+		evts = append(evts, evt)
+	}
+
+	for key, newStat := range curr.Validators {
+		oldStat, ok := prev.Validators[key]
+		if !ok {
+			evts = append(evts, &ValidatorEvent{
+				Type:     ValidatorJoined,
+				Height:   curr.Height,
+				Address:  newStat.Address,
+				NewPower: newStat.Power,
+			})
+			continue
+		}
+
+		if newStat.Power != oldStat.Power {
+			evts = append(evts, &ValidatorEvent{
+				Type:     ValidatorPowerChanged,
+				Height:   curr.Height,
+				Address:  newStat.Address,
+				OldPower: oldStat.Power,
+				NewPower: newStat.Power,
+			})
+		}
+		// The count is reset every inflation cycle, so only increases are reported.
+		if newStat.NotSignedBlockCount > oldStat.NotSignedBlockCount {
+			evts = append(evts, &ValidatorEvent{
+				Type:            ValidatorMissedBlocks,
+				Height:          curr.Height,
+				Address:         newStat.Address,
+				OldPower:        oldStat.Power,
+				NewPower:        newStat.Power,
+				OldMissedBlocks: oldStat.NotSignedBlockCount,
+				NewMissedBlocks: newStat.NotSignedBlockCount,
+				MaxMissedBlocks: curr.MaxMissedBlocks,
+			})
+		}
+	}
+
+	sort.SliceStable(evts, func(i, j int) bool {
+		return evts[i].Address.String() < evts[j].Address.String()
+	})
+	return evts, nil
+}
+
+// slashings returns the ValidatorSlashed events of the watched validators
+// found in the begin block events from fromHeight to toHeight.
+func (m *ValidatorMonitor) slashings(fromHeight, toHeight int64) ([]*ValidatorEvent, error) {
+	var evts []*ValidatorEvent
+	for h := fromHeight; h <= toHeight; h++ {
+		results, err := m.bzweb3.QueryBlockResults(h)
+		if err != nil {
+			return nil, err
+		}
+		for _, bevt := range results.BeginBlockEvents {
+			if bevt.Type != "vpower.slashing" {
+				continue
+			}
+			evt := &ValidatorEvent{Type: ValidatorSlashed, Height: h}
+			for _, attr := range bevt.Attributes {
+				switch string(attr.Key) {
+				case "byzantine":
+					if evt.Address, err = btztypes.HexToAddress(string(attr.Value)); err != nil {
+						return nil, fmt.Errorf("wrong address of slashing event at %v: %w", h, err)
+					}
+				case "type":
+					evt.Evidence = string(attr.Value)
+				case "slashed":
+					if evt.SlashedPower, err = strconv.ParseInt(string(attr.Value), 10, 64); err != nil {
+						return nil, fmt.Errorf("wrong slashed power of slashing event at %v: %w", h, err)
+					}
+				}
+			}
+			if evt.Address != nil && m.isWatched(evt.Address.String()) {
+				evts = append(evts, evt)
+			}
+		}
+	}
+	return evts, nil
+}
+
+// removedForMissedBlocks returns true if the delegatee of addr is removed between fromHeight and toHeight
+// at a height whose last commit shows addr didn't sign.
+// The node removes the delegatee of a validator for missed blocks when it begins a block
+// whose last commit lacks the validator's signature, while the operator's unstaking removes it by a tx.
+func (m *ValidatorMonitor) removedForMissedBlocks(addr btztypes.Address, fromHeight, toHeight int64) (bool, error) {
+	for h := fromHeight; h <= toHeight; h++ {
+		found, err := m.delegateeExists(addr, h)
+		if err != nil {
+			return false, err
+		}
+		if found {
+			continue
+		}
+
+		blk, err := m.bzweb3.QueryBlock(h)
+		if err != nil {
+			return false, err
+		}
+		if blk.Block == nil || blk.Block.LastCommit == nil {
+			return false, nil
+		}
+		for _, sig := range blk.Block.LastCommit.Signatures {
+			if bytes.Equal(sig.ValidatorAddress, addr) {
+				return sig.BlockIDFlag == tmtypes.BlockIDFlagAbsent, nil
+			}
+		}
+		return false, nil
+	}
+	return false, nil
+}
+
+// delegateeExists returns false if the node answers that addr is not a delegatee at height.
+// Any other failure of the query, e.g. at a pruned height, is returned as an error
+// so that it is not mistaken for a removed delegatee.
+func (m *ValidatorMonitor) delegateeExists(addr btztypes.Address, height int64) (bool, error) {
+	queryResp := &rpc.QueryResult{}
+	if req, err := m.bzweb3.NewRequest("delegatee", addr.String(), strconv.FormatInt(height, 10)); err != nil {
+		return false, err
+	} else if resp, err := m.bzweb3.provider.Call(req); err != nil {
+		return false, err
+	} else if resp.Error != nil {
+		return false, errors.New("provider error: " + string(resp.Error))
+	} else if err := tmjson.Unmarshal(resp.Result, queryResp); err != nil {
+		return false, err
+	}
+	switch {
+	case queryResp.Code == 0:
+		return len(queryResp.Value) > 0, nil
+	case isNotFoundQuery(queryResp):
+		return false, nil
+	default:
+		return false, fmt.Errorf("delegatee query failed: code:%v, log:%v", queryResp.Code, queryResp.Log)
+	}
+}
+
+// isNotFoundQuery returns true if the query failed because the queried item doesn't exist.
+// The node wraps the not-found error of its ledger in a query error, so the log is checked too.
+func isNotFoundQuery(queryResp *rpc.QueryResult) bool {
+	switch queryResp.Code {
+	case xerrors.ErrCodeNotFoundDelegatee, xerrors.ErrCodeNotFoundResult:
+		return true
+	case xerrors.ErrCodeQuery:
+		return strings.Contains(queryResp.Log, xerrors.ErrNotFoundResult.Error())
+	}
+	return false
+}
+
+// Start polls the latest height every interval until Stop is called.
+func (m *ValidatorMonitor) Start() error {
+	if _, err := m.Check(0); err != nil {
+		return err
+	}
+
+	done := m.resetDone()
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if _, err := m.Check(0); err != nil {
+					m.logger.Warn("validator monitor check failed", "err", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// StartWithSubscriber checks the height of every new block header received by sub
+// instead of polling. Stop the subscriber to stop the monitor.
+func (m *ValidatorMonitor) StartWithSubscriber(sub *Subscriber) error {
+	if _, err := m.Check(0); err != nil {
+		return err
+	}
+
+	return sub.Start(tmtypes.EventQueryNewBlockHeader.String(), func(_ *Subscriber, result []byte) {
+		evt := &coretypes.ResultEvent{}
+		if err := tmjson.Unmarshal(result, evt); err != nil {
+			m.logger.Warn("validator monitor dropped event", "err", err)
+			return
+		}
+		data, ok := evt.Data.(tmtypes.EventDataNewBlockHeader)
+		if !ok {
+			m.logger.Warn("validator monitor dropped event", "reason", "not a new block header")
+			return
+		}
+		if _, err := m.Check(data.Header.Height); err != nil {
+			m.logger.Warn("validator monitor check failed", "height", data.Header.Height, "err", err)
+		}
+	})
+}
+
+func (m *ValidatorMonitor) Stop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.done != nil {
+		close(m.done)
+		m.done = nil
+	}
+}
+
+func (m *ValidatorMonitor) resetDone() chan struct{} {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.done != nil {
+		close(m.done)
+	}
+	m.done = make(chan struct{})
+	return m.done
+}
+
+// Last returns the last snapshot taken by Check.
+func (m *ValidatorMonitor) Last() *ValidatorSnapshot {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.last
+}
@@ -204,6 +204,17 @@ func (w *Wallet) SendTxCommit(tx *ctrlertypes.Trx, bzweb3 *BeatozWeb3) (*coretyp
 	}
 }
 
+// CheckTx signs tx and runs it by CheckTx without broadcasting.
+// It advances the nonce and the balance of w on the node until the next block, as BeatozWeb3.CheckTx does,
+// so the tx can't be broadcast to the node until then.
+func (w *Wallet) CheckTx(tx *ctrlertypes.Trx, bzweb3 *BeatozWeb3) (*coretypes.ResultCheckTx, error) {
+	if _, _, err := w.SignTrxRLP(tx, bzweb3.ChainID()); err != nil {
+		return nil, err
+	} else {
+		return bzweb3.CheckTx(tx)
+	}
+}
+
 func (w *Wallet) SetDocSync(name, url string, gas int64, gasPrice *uint256.Int, bzweb3 *BeatozWeb3) (*coretypes.ResultBroadcastTx, error) {
 	tx := NewTrxSetDoc(w.Address(), w.acct.GetNonce(), gas, gasPrice, name, url)
 	if _, _, err := w.SignTrxRLP(tx, bzweb3.ChainID()); err != nil {